@@ -8,6 +8,22 @@ func (fr *Fr) String() string {
 	return FrStr(fr)
 }
 
+// ExpModFrUint64 computes v**e in Fr, like ExpModFr, for a small exponent, with square-and-multiply.
+func ExpModFrUint64(dst *Fr, v *Fr, e uint64) {
+	var out, base, tmp Fr
+	CopyFr(&out, &ONE)
+	CopyFr(&base, v)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			MulModFr(&tmp, &out, &base)
+			CopyFr(&out, &tmp)
+		}
+		MulModFr(&tmp, &base, &base)
+		CopyFr(&base, &tmp)
+	}
+	CopyFr(dst, &out)
+}
+
 // Checks if a *little endian* uint256 is within the Fr modulus
 func ValidFr(val [32]byte) bool {
 	if val[31] == 0 { // common to just use bytes31
@@ -0,0 +1,183 @@
+//go:build bignum_blst
+// +build bignum_blst
+
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"unsafe"
+
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+var _modulus big.Int
+
+func init() {
+	if err := _modulus.UnmarshalText([]byte(ModulusStr)); err != nil {
+		panic(err)
+	}
+	initGlobals()
+	ClearG1(&ZERO_G1)
+	initG1G2()
+}
+
+// Note: a blst scalar is a plain (not Montgomery) little-endian 32 byte number,
+// so encoding, copying and comparisons are done in Go, only the modular arithmetic calls into blst.
+type Fr blst.Scalar
+
+func frBytes(v *Fr) *[32]byte {
+	// the scalar is a single byte array, this is just a view of it.
+	return (*[32]byte)(unsafe.Pointer(v))
+}
+
+func SetFr(dst *Fr, v string) {
+	var bv big.Int
+	if _, ok := bv.SetString(v, 10); !ok {
+		panic("invalid Fr string: " + v)
+	}
+	bv.Mod(&bv, &_modulus)
+	var be [32]byte
+	bv.FillBytes(be[:])
+	out := frBytes(dst)
+	for i := 0; i < 32; i++ {
+		out[i] = be[31-i]
+	}
+}
+
+// FrFrom32 mutates the fr num. The value v is little-endian 32-bytes.
+// Returns false, without modifying dst, if the value is out of range.
+func FrFrom32(dst *Fr, v [32]byte) (ok bool) {
+	if !ValidFr(v) {
+		return false
+	}
+	*frBytes(dst) = v
+	return true
+}
+
+// FrTo32 serializes a fr number to 32 bytes. Encoded little-endian.
+func FrTo32(src *Fr) (v [32]byte) {
+	return *frBytes(src)
+}
+
+func CopyFr(dst *Fr, v *Fr) {
+	*dst = *v
+}
+
+func AsFr(dst *Fr, i uint64) {
+	var out [32]byte
+	binary.LittleEndian.PutUint64(out[:8], i)
+	*frBytes(dst) = out
+}
+
+func FrStr(b *Fr) string {
+	if b == nil {
+		return "<nil>"
+	}
+	v := *frBytes(b)
+	// reverse endianness, big.Int takes big-endian bytes
+	for i := 0; i < 16; i++ {
+		v[i], v[31-i] = v[31-i], v[i]
+	}
+	return new(big.Int).SetBytes(v[:]).String()
+}
+
+func EqualOne(v *Fr) bool {
+	return *frBytes(v) == [32]byte{0: 1}
+}
+
+func EqualZero(v *Fr) bool {
+	return *frBytes(v) == [32]byte{}
+}
+
+func EqualFr(a *Fr, b *Fr) bool {
+	return *frBytes(a) == *frBytes(b)
+}
+
+func RandomFr() *Fr {
+	v, err := rand.Int(rand.Reader, &_modulus)
+	if err != nil {
+		panic(err)
+	}
+	var out Fr
+	SetFr(&out, v.String())
+	return &out
+}
+
+// Note: the blst scalar arithmetic reports whether the result is non-zero, which we do not need.
+
+func SubModFr(dst *Fr, a, b *Fr) {
+	out, _ := (*blst.Scalar)(a).Sub((*blst.Scalar)(b))
+	*dst = Fr(*out)
+}
+
+func AddModFr(dst *Fr, a, b *Fr) {
+	out, _ := (*blst.Scalar)(a).Add((*blst.Scalar)(b))
+	*dst = Fr(*out)
+}
+
+func DivModFr(dst *Fr, a, b *Fr) {
+	out, _ := (*blst.Scalar)(a).Mul((*blst.Scalar)(b).Inverse())
+	*dst = Fr(*out)
+}
+
+func MulModFr(dst *Fr, a, b *Fr) {
+	out, _ := (*blst.Scalar)(a).Mul((*blst.Scalar)(b))
+	*dst = Fr(*out)
+}
+
+func InvModFr(dst *Fr, v *Fr) {
+	*dst = Fr(*(*blst.Scalar)(v).Inverse())
+}
+
+// BatchInvModFr computes the inverse for each input, with a single field inversion (Montgomery's trick).
+// Zero inputs are left as zero.
+func BatchInvModFr(f []Fr) {
+	if len(f) == 0 {
+		return
+	}
+	// prefix[i] is the product of the non-zero inputs before i
+	prefix := make([]Fr, len(f), len(f))
+	var acc Fr
+	CopyFr(&acc, &ONE)
+	for i := range f {
+		CopyFr(&prefix[i], &acc)
+		if !EqualZero(&f[i]) {
+			MulModFr(&acc, &acc, &f[i])
+		}
+	}
+	InvModFr(&acc, &acc)
+	var tmp Fr
+	for i := len(f) - 1; i >= 0; i-- {
+		if EqualZero(&f[i]) {
+			continue
+		}
+		MulModFr(&tmp, &acc, &prefix[i])
+		MulModFr(&acc, &acc, &f[i])
+		CopyFr(&f[i], &tmp)
+	}
+}
+
+//func SqrModFr(dst *Fr, v *Fr) {
+//	MulModFr(dst, v, v)
+//}
+
+func EvalPolyAt(dst *Fr, p []Fr, x *Fr) {
+	// TODO: blst has no polynomial evaluation function
+	EvalPolyAtUnoptimized(dst, p, x)
+}
+
+// ExpModFr computes v**e in Fr, with square-and-multiply.
+func ExpModFr(dst *Fr, v *Fr, e *big.Int) {
+	var out, base Fr
+	CopyFr(&out, &ONE)
+	CopyFr(&base, v)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		MulModFr(&out, &out, &out)
+		if e.Bit(i) == 1 {
+			MulModFr(&out, &out, &base)
+		}
+	}
+	CopyFr(dst, &out)
+}
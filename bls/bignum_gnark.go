@@ -0,0 +1,132 @@
+//go:build bignum_gnark
+// +build bignum_gnark
+
+package bls
+
+import (
+	"math/big"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func init() {
+	initGlobals()
+	ClearG1(&ZERO_G1)
+	initG1G2()
+}
+
+// Note: gnark-crypto represents Fr in Montgomery form internally,
+// conversions to and from bytes and big ints take care of the reduction.
+type Fr fr.Element
+
+func SetFr(dst *Fr, v string) {
+	if _, err := (*fr.Element)(dst).SetString(v); err != nil {
+		panic(err)
+	}
+}
+
+// FrFrom32 mutates the fr num. The value v is little-endian 32-bytes.
+// Returns false, without modifying dst, if the value is out of range.
+func FrFrom32(dst *Fr, v [32]byte) (ok bool) {
+	if !ValidFr(v) {
+		return false
+	}
+	// reverse endianness, gnark Fr takes big-endian bytes
+	for i := 0; i < 16; i++ {
+		v[i], v[31-i] = v[31-i], v[i]
+	}
+	(*fr.Element)(dst).SetBytes(v[:])
+	return true
+}
+
+// FrTo32 serializes a fr number to 32 bytes. Encoded little-endian.
+func FrTo32(src *Fr) (v [32]byte) {
+	v = (*fr.Element)(src).Bytes()
+	// reverse endianness, gnark Fr outputs big-endian bytes
+	for i := 0; i < 16; i++ {
+		v[i], v[31-i] = v[31-i], v[i]
+	}
+	return
+}
+
+func CopyFr(dst *Fr, v *Fr) {
+	*dst = *v
+}
+
+func AsFr(dst *Fr, i uint64) {
+	(*fr.Element)(dst).SetUint64(i)
+}
+
+func FrStr(b *Fr) string {
+	if b == nil {
+		return "<nil>"
+	}
+	// not fr.Element.String, that prints small negative numbers with a minus sign
+	var v big.Int
+	return (*fr.Element)(b).BigInt(&v).String()
+}
+
+func EqualOne(v *Fr) bool {
+	return (*fr.Element)(v).IsOne()
+}
+
+func EqualZero(v *Fr) bool {
+	return (*fr.Element)(v).IsZero()
+}
+
+func EqualFr(a *Fr, b *Fr) bool {
+	return (*fr.Element)(a).Equal((*fr.Element)(b))
+}
+
+func RandomFr() *Fr {
+	var out fr.Element
+	if _, err := out.SetRandom(); err != nil {
+		panic(err)
+	}
+	return (*Fr)(&out)
+}
+
+func SubModFr(dst *Fr, a, b *Fr) {
+	(*fr.Element)(dst).Sub((*fr.Element)(a), (*fr.Element)(b))
+}
+
+func AddModFr(dst *Fr, a, b *Fr) {
+	(*fr.Element)(dst).Add((*fr.Element)(a), (*fr.Element)(b))
+}
+
+func DivModFr(dst *Fr, a, b *Fr) {
+	var tmp fr.Element
+	tmp.Inverse((*fr.Element)(b))
+	(*fr.Element)(dst).Mul(&tmp, (*fr.Element)(a))
+}
+
+func MulModFr(dst *Fr, a, b *Fr) {
+	(*fr.Element)(dst).Mul((*fr.Element)(a), (*fr.Element)(b))
+}
+
+func InvModFr(dst *Fr, v *Fr) {
+	(*fr.Element)(dst).Inverse((*fr.Element)(v))
+}
+
+// BatchInvModFr computes the inverse for each input, with a single field inversion.
+func BatchInvModFr(f []Fr) {
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	inv := fr.BatchInvert(*(*[]fr.Element)(unsafe.Pointer(&f)))
+	for i := range f {
+		f[i] = Fr(inv[i])
+	}
+}
+
+//func SqrModFr(dst *Fr, v *Fr) {
+//	(*fr.Element)(dst).Square((*fr.Element)(v))
+//}
+
+func EvalPolyAt(dst *Fr, p []Fr, x *Fr) {
+	// TODO: gnark-crypto has no polynomial evaluation function for plain Fr slices
+	EvalPolyAtUnoptimized(dst, p, x)
+}
+
+func ExpModFr(dst *Fr, v *Fr, e *big.Int) {
+	(*fr.Element)(dst).Exp(*(*fr.Element)(v), e)
+}
@@ -4,6 +4,7 @@
 package bls
 
 import (
+	"encoding/binary"
 	"math/big"
 	"unsafe"
 
@@ -57,7 +58,12 @@ func CopyFr(dst *Fr, v *Fr) {
 }
 
 func AsFr(dst *Fr, i uint64) {
-	(*hbls.Fr)(dst).SetInt64(int64(i))
+	// not SetInt64, that would make numbers of 2**63 and above negative
+	var data [8]byte
+	binary.LittleEndian.PutUint64(data[:], i)
+	if err := (*hbls.Fr)(dst).SetLittleEndian(data[:]); err != nil {
+		panic(err)
+	}
 }
 
 func FrStr(b *Fr) string {
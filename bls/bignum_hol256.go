@@ -45,13 +45,12 @@ func FrFrom32(dst *Fr, v [32]byte) (ok bool) {
 
 // FrTo32 serializes a fr number to 32 bytes. Encoded little-endian.
 func FrTo32(src *Fr) (v [32]byte) {
-	b := (*u256.Int)(src).Bytes()
-	last := len(b) - 1
+	// zero-padded to 32 bytes, u256.Int.Bytes would be shorter for small numbers
+	v = (*u256.Int)(src).Bytes32()
 	// reverse endianness, u256.Int outputs big-endian bytes
 	for i := 0; i < 16; i++ {
-		b[i], b[last-i] = b[last-i], b[i]
+		v[i], v[31-i] = v[31-i], v[i]
 	}
-	copy(v[:], b)
 	return
 }
 
@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256 && !bignum_hbls
-// +build !bignum_pure,!bignum_hol256,!bignum_hbls
+//go:build !bignum_pure && !bignum_hol256 && !bignum_hbls && !bignum_gnark && !bignum_blst
+// +build !bignum_pure,!bignum_hol256,!bignum_hbls,!bignum_gnark,!bignum_blst
 
 package bls
 
@@ -33,13 +33,12 @@ func FrFrom32(dst *Fr, v [32]byte) (ok bool) {
 
 // FrTo32 serializes a fr number to 32 bytes. Encoded little-endian.
 func FrTo32(src *Fr) (v [32]byte) {
-	b := (*big.Int)(src).Bytes()
-	last := len(b) - 1
-	// reverse endianness, u256.Int outputs big-endian bytes
+	// zero-padded to 32 bytes, big.Int.Bytes would be shorter for small numbers
+	(*big.Int)(src).FillBytes(v[:])
+	// reverse endianness, big.Int outputs big-endian bytes
 	for i := 0; i < 16; i++ {
-		b[i], b[last-i] = b[last-i], b[i]
+		v[i], v[31-i] = v[31-i], v[i]
 	}
-	copy(v[:], b)
 	return
 }
 
@@ -114,3 +114,19 @@ func TestValidFr(t *testing.T) {
 		t.Fatal("expected zero to be valid")
 	}
 }
+
+func TestExpModFrUint64(t *testing.T) {
+	var x Fr
+	AsFr(&x, 5431)
+	var expected, tmp Fr
+	CopyFr(&expected, &ONE)
+	for n := uint64(0); n < 70; n++ {
+		var got Fr
+		ExpModFrUint64(&got, &x, n)
+		if !EqualFr(&got, &expected) {
+			t.Fatalf("x^%d: got %s, expected %s", n, FrStr(&got), FrStr(&expected))
+		}
+		MulModFr(&tmp, &expected, &x)
+		CopyFr(&expected, &tmp)
+	}
+}
@@ -0,0 +1,273 @@
+//go:build bignum_blst
+// +build bignum_blst
+
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unsafe"
+
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+var ZERO_G1 G1Point
+
+var GenG1 G1Point
+var GenG2 G2Point
+
+var ZeroG1 G1Point
+var ZeroG2 G2Point
+
+func initG1G2() {
+	GenG1 = G1Point(*blst.P1Generator())
+	GenG2 = G2Point(*blst.P2Generator())
+	ClearG1(&ZeroG1)
+	ClearG2(&ZeroG2)
+}
+
+// number of bits of the scalars passed to blst, the Fr modulus is smaller than 2**255
+const scalarBits = 255
+
+// Points are kept in Jacobian coordinates, and converted to affine form for MSMs, pairings and encoding.
+type G1Point blst.P1
+
+// zeroes the point (like herumi BLS does with theirs). This is not co-factor clearing.
+func ClearG1(x *G1Point) {
+	*x = G1Point{}
+}
+
+func CopyG1(dst *G1Point, v *G1Point) {
+	*dst = *v
+}
+
+func MulG1(dst *G1Point, a *G1Point, b *Fr) {
+	*dst = G1Point(*(*blst.P1)(a).Mult((*blst.Scalar)(b), scalarBits))
+}
+
+func AddG1(dst *G1Point, a *G1Point, b *G1Point) {
+	*dst = G1Point(*(*blst.P1)(a).Add((*blst.P1)(b)))
+}
+
+func SubG1(dst *G1Point, a *G1Point, b *G1Point) {
+	*dst = G1Point(*(*blst.P1)(a).Sub((*blst.P1)(b)))
+}
+
+func StrG1(v *G1Point) string {
+	data := (*blst.P1)(v).Serialize()
+	var a, b big.Int
+	a.SetBytes(data[:48])
+	b.SetBytes(data[48:])
+	return a.String() + "\n" + b.String()
+}
+
+func NegG1(dst *G1Point) {
+	var out blst.P1
+	out.SubAssign((*blst.P1)(dst))
+	*dst = G1Point(out)
+}
+
+type G2Point blst.P2
+
+// zeroes the point (like herumi BLS does with theirs). This is not co-factor clearing.
+func ClearG2(x *G2Point) {
+	*x = G2Point{}
+}
+
+func CopyG2(dst *G2Point, v *G2Point) {
+	*dst = *v
+}
+
+func MulG2(dst *G2Point, a *G2Point, b *Fr) {
+	*dst = G2Point(*(*blst.P2)(a).Mult((*blst.Scalar)(b), scalarBits))
+}
+
+func AddG2(dst *G2Point, a *G2Point, b *G2Point) {
+	*dst = G2Point(*(*blst.P2)(a).Add((*blst.P2)(b)))
+}
+
+func SubG2(dst *G2Point, a *G2Point, b *G2Point) {
+	*dst = G2Point(*(*blst.P2)(a).Sub((*blst.P2)(b)))
+}
+
+func NegG2(dst *G2Point) {
+	var out blst.P2
+	out.SubAssign((*blst.P2)(dst))
+	*dst = G2Point(out)
+}
+
+func StrG2(v *G2Point) string {
+	data := (*blst.P2)(v).Serialize()
+	var a, b big.Int
+	a.SetBytes(data[:96])
+	b.SetBytes(data[96:])
+	return a.String() + "\n" + b.String()
+}
+
+func EqualG1(a *G1Point, b *G1Point) bool {
+	return (*blst.P1)(a).Equals((*blst.P1)(b))
+}
+
+func EqualG2(a *G2Point, b *G2Point) bool {
+	return (*blst.P2)(a).Equals((*blst.P2)(b))
+}
+
+// IsValidG1 checks that the point is on the curve and in the G1 subgroup. The point at infinity is valid.
+func IsValidG1(p *G1Point) bool {
+	// the Go bindings have no on-curve check, but decoding the uncompressed point does one.
+	var aff blst.P1Affine
+	return aff.Deserialize((*blst.P1)(p).Serialize()) != nil && aff.InG1()
+}
+
+// IsValidG2 checks that the point is on the curve and in the G2 subgroup. The point at infinity is valid.
+func IsValidG2(p *G2Point) bool {
+	var aff blst.P2Affine
+	return aff.Deserialize((*blst.P2)(p).Serialize()) != nil && aff.InG2()
+}
+
+func ToCompressedG1(p *G1Point) []byte {
+	return (*blst.P1)(p).Compress()
+}
+
+func fromCompressedG1(v []byte) (*G1Point, error) {
+	var aff blst.P1Affine
+	if aff.Uncompress(v) == nil {
+		return nil, errors.New("invalid compressed G1 point")
+	}
+	return fromAffineG1(&aff)
+}
+
+func ToUncompressedG1(p *G1Point) []byte {
+	return (*blst.P1)(p).Serialize()
+}
+
+func fromUncompressedG1(v []byte) (*G1Point, error) {
+	var aff blst.P1Affine
+	if aff.Deserialize(v) == nil {
+		return nil, errors.New("invalid uncompressed G1 point")
+	}
+	return fromAffineG1(&aff)
+}
+
+func fromAffineG1(aff *blst.P1Affine) (*G1Point, error) {
+	// decoding only checks the point is on the curve
+	if !aff.InG1() {
+		return nil, errors.New("point is not on correct subgroup")
+	}
+	var out blst.P1
+	out.FromAffine(aff)
+	return (*G1Point)(&out), nil
+}
+
+func ToCompressedG2(p *G2Point) []byte {
+	return (*blst.P2)(p).Compress()
+}
+
+func fromCompressedG2(v []byte) (*G2Point, error) {
+	var aff blst.P2Affine
+	if aff.Uncompress(v) == nil {
+		return nil, errors.New("invalid compressed G2 point")
+	}
+	return fromAffineG2(&aff)
+}
+
+func ToUncompressedG2(p *G2Point) []byte {
+	return (*blst.P2)(p).Serialize()
+}
+
+func fromUncompressedG2(v []byte) (*G2Point, error) {
+	var aff blst.P2Affine
+	if aff.Deserialize(v) == nil {
+		return nil, errors.New("invalid uncompressed G2 point")
+	}
+	return fromAffineG2(&aff)
+}
+
+func fromAffineG2(aff *blst.P2Affine) (*G2Point, error) {
+	// decoding only checks the point is on the curve
+	if !aff.InG2() {
+		return nil, errors.New("point is not on correct subgroup")
+	}
+	var out blst.P2
+	out.FromAffine(aff)
+	return (*G2Point)(&out), nil
+}
+
+// LinCombG1 computes the MSM with the Pippenger implementation of blst.
+func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
+	if len(numbers) != len(factors) {
+		panic("got LinCombG1 numbers/factors length mismatch")
+	}
+	var out G1Point
+	if len(numbers) == 0 {
+		return &out
+	}
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	points := *(*blst.P1s)(unsafe.Pointer(&numbers))
+	scalars := *(*[]blst.Scalar)(unsafe.Pointer(&factors))
+	out = G1Point(*points.Mult(scalars, scalarBits))
+	return &out
+}
+
+// LinCombG2 computes the MSM with the Pippenger implementation of blst.
+func LinCombG2(numbers []G2Point, factors []Fr) *G2Point {
+	if len(numbers) != len(factors) {
+		panic("got LinCombG2 numbers/factors length mismatch")
+	}
+	var out G2Point
+	if len(numbers) == 0 {
+		return &out
+	}
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	points := *(*blst.P2s)(unsafe.Pointer(&numbers))
+	scalars := *(*[]blst.Scalar)(unsafe.Pointer(&factors))
+	out = G2Point(*points.Mult(scalars, scalarBits))
+	return &out
+}
+
+// e(a1^(-1), a2) * e(b1,  b2) = 1_T
+func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
+	var g1s [2]G1Point
+	CopyG1(&g1s[0], a1)
+	NegG1(&g1s[0])
+	CopyG1(&g1s[1], b1)
+	g2s := [2]G2Point{*a2, *b2}
+	return PairingsCheck(g1s[:], g2s[:])
+}
+
+// PairingsCheck checks if the product of the pairings e(g1s[i], g2s[i]) equals 1_T.
+// The Miller loops of all pairs share a single final exponentiation.
+func PairingsCheck(g1s []G1Point, g2s []G2Point) bool {
+	if len(g1s) != len(g2s) {
+		panic("got PairingsCheck g1s/g2s length mismatch")
+	}
+	// pairs with the point at infinity contribute 1_T, and are left out of the Miller loop
+	ps := make([]blst.P1Affine, 0, len(g1s))
+	qs := make([]blst.P2Affine, 0, len(g2s))
+	var zero1 G1Point
+	var zero2 G2Point
+	for i := range g1s {
+		if EqualG1(&g1s[i], &zero1) || EqualG2(&g2s[i], &zero2) {
+			continue
+		}
+		ps = append(ps, *(*blst.P1)(&g1s[i]).ToAffine())
+		qs = append(qs, *(*blst.P2)(&g2s[i]).ToAffine())
+	}
+	if len(ps) == 0 {
+		return true
+	}
+	ml := blst.Fp12MillerLoopN(qs, ps)
+	ml.FinalExp()
+	one := blst.Fp12One()
+	return ml.Equals(&one)
+}
+
+func DebugG1s(msg string, values []G1Point) {
+	var out strings.Builder
+	for i := range values {
+		out.WriteString(fmt.Sprintf("%s %d: %s\n", msg, i, StrG1(&values[i])))
+	}
+	fmt.Println(out.String())
+}
@@ -0,0 +1,293 @@
+//go:build bignum_gnark
+// +build bignum_gnark
+
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gbls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var ZERO_G1 G1Point
+
+var GenG1 G1Point
+var GenG2 G2Point
+
+var ZeroG1 G1Point
+var ZeroG2 G2Point
+
+func initG1G2() {
+	g1, g2, _, _ := gbls.Generators()
+	GenG1 = G1Point(g1)
+	GenG2 = G2Point(g2)
+	ClearG1(&ZeroG1)
+	ClearG2(&ZeroG2)
+}
+
+// Points are kept in Jacobian coordinates, and converted to affine form for MSMs, pairings and encoding.
+type G1Point gbls.G1Jac
+
+// zeroes the point (like herumi BLS does with theirs). This is not co-factor clearing.
+func ClearG1(x *G1Point) {
+	x.X.SetOne()
+	x.Y.SetOne()
+	x.Z.SetZero()
+}
+
+func CopyG1(dst *G1Point, v *G1Point) {
+	*dst = *v
+}
+
+func MulG1(dst *G1Point, a *G1Point, b *Fr) {
+	var s big.Int
+	(*fr.Element)(b).BigInt(&s)
+	(*gbls.G1Jac)(dst).ScalarMultiplication((*gbls.G1Jac)(a), &s)
+}
+
+func AddG1(dst *G1Point, a *G1Point, b *G1Point) {
+	tmp := *(*gbls.G1Jac)(a) // copy, dst may alias b
+	tmp.AddAssign((*gbls.G1Jac)(b))
+	*dst = G1Point(tmp)
+}
+
+func SubG1(dst *G1Point, a *G1Point, b *G1Point) {
+	tmp := *(*gbls.G1Jac)(a) // copy, dst may alias b
+	tmp.SubAssign((*gbls.G1Jac)(b))
+	*dst = G1Point(tmp)
+}
+
+func toAffineG1(p *G1Point) *gbls.G1Affine {
+	var out gbls.G1Affine
+	out.FromJacobian((*gbls.G1Jac)(p))
+	return &out
+}
+
+func StrG1(v *G1Point) string {
+	data := toAffineG1(v).RawBytes()
+	var a, b big.Int
+	a.SetBytes(data[:48])
+	b.SetBytes(data[48:])
+	return a.String() + "\n" + b.String()
+}
+
+func NegG1(dst *G1Point) {
+	(*gbls.G1Jac)(dst).Neg((*gbls.G1Jac)(dst))
+}
+
+type G2Point gbls.G2Jac
+
+// zeroes the point (like herumi BLS does with theirs). This is not co-factor clearing.
+func ClearG2(x *G2Point) {
+	x.X.SetOne()
+	x.Y.SetOne()
+	x.Z.SetZero()
+}
+
+func CopyG2(dst *G2Point, v *G2Point) {
+	*dst = *v
+}
+
+func MulG2(dst *G2Point, a *G2Point, b *Fr) {
+	var s big.Int
+	(*fr.Element)(b).BigInt(&s)
+	(*gbls.G2Jac)(dst).ScalarMultiplication((*gbls.G2Jac)(a), &s)
+}
+
+func AddG2(dst *G2Point, a *G2Point, b *G2Point) {
+	tmp := *(*gbls.G2Jac)(a) // copy, dst may alias b
+	tmp.AddAssign((*gbls.G2Jac)(b))
+	*dst = G2Point(tmp)
+}
+
+func SubG2(dst *G2Point, a *G2Point, b *G2Point) {
+	tmp := *(*gbls.G2Jac)(a) // copy, dst may alias b
+	tmp.SubAssign((*gbls.G2Jac)(b))
+	*dst = G2Point(tmp)
+}
+
+func NegG2(dst *G2Point) {
+	(*gbls.G2Jac)(dst).Neg((*gbls.G2Jac)(dst))
+}
+
+func toAffineG2(p *G2Point) *gbls.G2Affine {
+	var out gbls.G2Affine
+	out.FromJacobian((*gbls.G2Jac)(p))
+	return &out
+}
+
+func StrG2(v *G2Point) string {
+	data := toAffineG2(v).RawBytes()
+	var a, b big.Int
+	a.SetBytes(data[:96])
+	b.SetBytes(data[96:])
+	return a.String() + "\n" + b.String()
+}
+
+func EqualG1(a *G1Point, b *G1Point) bool {
+	return (*gbls.G1Jac)(a).Equal((*gbls.G1Jac)(b))
+}
+
+func EqualG2(a *G2Point, b *G2Point) bool {
+	return (*gbls.G2Jac)(a).Equal((*gbls.G2Jac)(b))
+}
+
+// IsValidG1 checks that the point is on the curve and in the G1 subgroup. The point at infinity is valid.
+func IsValidG1(p *G1Point) bool {
+	return (*gbls.G1Jac)(p).IsOnCurve() && (*gbls.G1Jac)(p).IsInSubGroup()
+}
+
+// IsValidG2 checks that the point is on the curve and in the G2 subgroup. The point at infinity is valid.
+func IsValidG2(p *G2Point) bool {
+	return (*gbls.G2Jac)(p).IsOnCurve() && (*gbls.G2Jac)(p).IsInSubGroup()
+}
+
+func ToCompressedG1(p *G1Point) []byte {
+	out := toAffineG1(p).Bytes()
+	return out[:]
+}
+
+// fromBytesG1 decodes either the compressed or the uncompressed form, the size is checked by the caller.
+func fromBytesG1(v []byte) (*G1Point, error) {
+	var a gbls.G1Affine
+	if _, err := a.SetBytes(v); err != nil {
+		return nil, err
+	}
+	var out gbls.G1Jac
+	out.FromAffine(&a)
+	// the uncompressed form is only checked for the subgroup, not for the curve
+	if !out.IsOnCurve() {
+		return nil, errors.New("point is not on curve")
+	}
+	return (*G1Point)(&out), nil
+}
+
+func fromCompressedG1(v []byte) (*G1Point, error) {
+	return fromBytesG1(v)
+}
+
+func ToUncompressedG1(p *G1Point) []byte {
+	out := toAffineG1(p).RawBytes()
+	return out[:]
+}
+
+func fromUncompressedG1(v []byte) (*G1Point, error) {
+	return fromBytesG1(v)
+}
+
+func ToCompressedG2(p *G2Point) []byte {
+	out := toAffineG2(p).Bytes()
+	return out[:]
+}
+
+// fromBytesG2 decodes either the compressed or the uncompressed form, the size is checked by the caller.
+func fromBytesG2(v []byte) (*G2Point, error) {
+	var a gbls.G2Affine
+	if _, err := a.SetBytes(v); err != nil {
+		return nil, err
+	}
+	var out gbls.G2Jac
+	out.FromAffine(&a)
+	// the uncompressed form is only checked for the subgroup, not for the curve
+	if !out.IsOnCurve() {
+		return nil, errors.New("point is not on curve")
+	}
+	return (*G2Point)(&out), nil
+}
+
+func fromCompressedG2(v []byte) (*G2Point, error) {
+	return fromBytesG2(v)
+}
+
+func ToUncompressedG2(p *G2Point) []byte {
+	out := toAffineG2(p).RawBytes()
+	return out[:]
+}
+
+func fromUncompressedG2(v []byte) (*G2Point, error) {
+	return fromBytesG2(v)
+}
+
+func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
+	if len(numbers) != len(factors) {
+		panic("got LinCombG1 numbers/factors length mismatch")
+	}
+	var out G1Point
+	if len(numbers) == 0 {
+		ClearG1(&out)
+		return &out
+	}
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	// The MSM takes affine points, the batch conversion shares a single field inversion.
+	points := gbls.BatchJacobianToAffineG1(*(*[]gbls.G1Jac)(unsafe.Pointer(&numbers)))
+	if _, err := (*gbls.G1Jac)(&out).MultiExp(points, *(*[]fr.Element)(unsafe.Pointer(&factors)), ecc.MultiExpConfig{}); err != nil {
+		panic(err)
+	}
+	return &out
+}
+
+func LinCombG2(numbers []G2Point, factors []Fr) *G2Point {
+	if len(numbers) != len(factors) {
+		panic("got LinCombG2 numbers/factors length mismatch")
+	}
+	var out G2Point
+	if len(numbers) == 0 {
+		ClearG2(&out)
+		return &out
+	}
+	points := make([]gbls.G2Affine, len(numbers), len(numbers))
+	for i := range numbers {
+		points[i].FromJacobian((*gbls.G2Jac)(&numbers[i]))
+	}
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	if _, err := (*gbls.G2Jac)(&out).MultiExp(points, *(*[]fr.Element)(unsafe.Pointer(&factors)), ecc.MultiExpConfig{}); err != nil {
+		panic(err)
+	}
+	return &out
+}
+
+// e(a1^(-1), a2) * e(b1,  b2) = 1_T
+func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
+	var g1s [2]G1Point
+	CopyG1(&g1s[0], a1)
+	NegG1(&g1s[0])
+	CopyG1(&g1s[1], b1)
+	g2s := [2]G2Point{*a2, *b2}
+	return PairingsCheck(g1s[:], g2s[:])
+}
+
+// PairingsCheck checks if the product of the pairings e(g1s[i], g2s[i]) equals 1_T.
+// The Miller loops of all pairs share a single final exponentiation.
+func PairingsCheck(g1s []G1Point, g2s []G2Point) bool {
+	if len(g1s) != len(g2s) {
+		panic("got PairingsCheck g1s/g2s length mismatch")
+	}
+	if len(g1s) == 0 {
+		return true
+	}
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	ps := gbls.BatchJacobianToAffineG1(*(*[]gbls.G1Jac)(unsafe.Pointer(&g1s)))
+	qs := make([]gbls.G2Affine, len(g2s), len(g2s))
+	for i := range g2s {
+		qs[i].FromJacobian((*gbls.G2Jac)(&g2s[i]))
+	}
+	ok, err := gbls.PairingCheck(ps, qs)
+	if err != nil {
+		panic(err)
+	}
+	return ok
+}
+
+func DebugG1s(msg string, values []G1Point) {
+	var out strings.Builder
+	for i := range values {
+		out.WriteString(fmt.Sprintf("%s %d: %s\n", msg, i, StrG1(&values[i])))
+	}
+	fmt.Println(out.String())
+}
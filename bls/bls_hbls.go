@@ -4,6 +4,7 @@
 package bls
 
 import (
+	"errors"
 	"fmt"
 	hbls "github.com/herumi/bls-eth-go-binary/bls"
 	"strings"
@@ -114,11 +115,21 @@ func EqualG2(a *G2Point, b *G2Point) bool {
 	return (*hbls.G2)(a).IsEqual((*hbls.G2)(b))
 }
 
+// IsValidG1 checks that the point is on the curve and in the G1 subgroup. The point at infinity is valid.
+func IsValidG1(p *G1Point) bool {
+	return (*hbls.G1)(p).IsValid() && (*hbls.G1)(p).IsValidOrder()
+}
+
+// IsValidG2 checks that the point is on the curve and in the G2 subgroup. The point at infinity is valid.
+func IsValidG2(p *G2Point) bool {
+	return (*hbls.G2)(p).IsValid() && (*hbls.G2)(p).IsValidOrder()
+}
+
 func ToCompressedG1(p *G1Point) []byte {
 	return hbls.CastToPublicKey((*hbls.G1)(p)).Serialize()
 }
 
-func FromCompressedG1(v []byte) (*G1Point, error) {
+func fromCompressedG1(v []byte) (*G1Point, error) {
 	var pub hbls.PublicKey
 	if err := pub.Deserialize(v); err != nil {
 		return nil, err
@@ -127,11 +138,27 @@ func FromCompressedG1(v []byte) (*G1Point, error) {
 	return (*G1Point)(p), nil
 }
 
+func ToUncompressedG1(p *G1Point) []byte {
+	return (*hbls.G1)(p).SerializeUncompressed()
+}
+
+func fromUncompressedG1(v []byte) (*G1Point, error) {
+	var p G1Point
+	if err := (*hbls.G1)(&p).DeserializeUncompressed(v); err != nil {
+		return nil, err
+	}
+	// unlike the compressed form, the coordinates are not checked when decoding
+	if !IsValidG1(&p) {
+		return nil, errors.New("point is not on curve or not in the subgroup")
+	}
+	return &p, nil
+}
+
 func ToCompressedG2(p *G2Point) []byte {
 	return hbls.CastToSign((*hbls.G2)(p)).Serialize()
 }
 
-func FromCompressedG2(v []byte) (*G2Point, error) {
+func fromCompressedG2(v []byte) (*G2Point, error) {
 	var sig hbls.Sign
 	if err := sig.Deserialize(v); err != nil {
 		return nil, err
@@ -140,6 +167,22 @@ func FromCompressedG2(v []byte) (*G2Point, error) {
 	return (*G2Point)(p), nil
 }
 
+func ToUncompressedG2(p *G2Point) []byte {
+	return (*hbls.G2)(p).SerializeUncompressed()
+}
+
+func fromUncompressedG2(v []byte) (*G2Point, error) {
+	var p G2Point
+	if err := (*hbls.G2)(&p).DeserializeUncompressed(v); err != nil {
+		return nil, err
+	}
+	// unlike the compressed form, the coordinates are not checked when decoding
+	if !IsValidG2(&p) {
+		return nil, errors.New("point is not on curve or not in the subgroup")
+	}
+	return &p, nil
+}
+
 func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
 	var out G1Point
 	// We're just using unsafe to cast elements that are an alias anyway, no problem.
@@ -148,32 +191,41 @@ func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
 	return &out
 }
 
+func LinCombG2(numbers []G2Point, factors []Fr) *G2Point {
+	if len(numbers) != len(factors) {
+		panic("got LinCombG2 numbers/factors length mismatch")
+	}
+	var out G2Point
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	hbls.G2MulVec((*hbls.G2)(&out), *(*[]hbls.G2)(unsafe.Pointer(&numbers)), *(*[]hbls.Fr)(unsafe.Pointer(&factors)))
+	return &out
+}
+
 // e(a1^(-1), a2) * e(b1,  b2) = 1_T
 func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
-	var tmp hbls.GT
-	hbls.Pairing(&tmp, (*hbls.G1)(a1), (*hbls.G2)(a2))
-	//fmt.Println("tmp", tmp.GetString(10))
-	var tmp2 hbls.GT
-	hbls.Pairing(&tmp2, (*hbls.G1)(b1), (*hbls.G2)(b2))
-
-	// invert left pairing
-	var tmp3 hbls.GT
-	hbls.GTInv(&tmp3, &tmp)
-
-	// multiply the two
-	var tmp4 hbls.GT
-	hbls.GTMul(&tmp4, &tmp3, &tmp2)
-
-	// final exp.
-	var tmp5 hbls.GT
-	hbls.FinalExp(&tmp5, &tmp4)
-
-	// = 1_T
-	return tmp5.IsOne()
-
-	// TODO, alternatively use the equal check (faster or slower?):
-	////fmt.Println("tmp2", tmp2.GetString(10))
-	//return tmp.IsEqual(&tmp2)
+	var g1s [2]G1Point
+	CopyG1(&g1s[0], a1)
+	NegG1(&g1s[0])
+	CopyG1(&g1s[1], b1)
+	g2s := [2]G2Point{*a2, *b2}
+	return PairingsCheck(g1s[:], g2s[:])
+}
+
+// PairingsCheck checks if the product of the pairings e(g1s[i], g2s[i]) equals 1_T.
+// The Miller loops of all pairs share a single final exponentiation.
+func PairingsCheck(g1s []G1Point, g2s []G2Point) bool {
+	if len(g1s) != len(g2s) {
+		panic("got PairingsCheck g1s/g2s length mismatch")
+	}
+	if len(g1s) == 0 {
+		return true
+	}
+	var ml hbls.GT
+	// We're just using unsafe to cast elements that are an alias anyway, no problem.
+	hbls.MillerLoopVec(&ml, *(*[]hbls.G1)(unsafe.Pointer(&g1s)), *(*[]hbls.G2)(unsafe.Pointer(&g2s)))
+	var out hbls.GT
+	hbls.FinalExp(&out, &ml)
+	return out.IsOne()
 }
 
 func DebugG1s(msg string, values []G1Point) {
@@ -1,5 +1,5 @@
-//go:build !bignum_pure && !bignum_hol256 && !bignum_hbls
-// +build !bignum_pure,!bignum_hol256,!bignum_hbls
+//go:build !bignum_pure && !bignum_hol256 && !bignum_hbls && !bignum_gnark && !bignum_blst
+// +build !bignum_pure,!bignum_hol256,!bignum_hbls,!bignum_gnark,!bignum_blst
 
 package bls
 
@@ -111,24 +111,54 @@ func EqualG2(a *G2Point, b *G2Point) bool {
 	return kbls.NewG2().Equal((*kbls.PointG2)(a), (*kbls.PointG2)(b))
 }
 
+// IsValidG1 checks that the point is on the curve and in the G1 subgroup. The point at infinity is valid.
+func IsValidG1(p *G1Point) bool {
+	g := kbls.NewG1()
+	return g.IsOnCurve((*kbls.PointG1)(p)) && g.InCorrectSubgroup((*kbls.PointG1)(p))
+}
+
+// IsValidG2 checks that the point is on the curve and in the G2 subgroup. The point at infinity is valid.
+func IsValidG2(p *G2Point) bool {
+	g := kbls.NewG2()
+	return g.IsOnCurve((*kbls.PointG2)(p)) && g.InCorrectSubgroup((*kbls.PointG2)(p))
+}
+
 func ToCompressedG1(p *G1Point) []byte {
 	return kbls.NewG1().ToCompressed((*kbls.PointG1)(p))
 }
 
-func FromCompressedG1(v []byte) (*G1Point, error) {
+func fromCompressedG1(v []byte) (*G1Point, error) {
 	p, err := kbls.NewG1().FromCompressed(v)
 	return (*G1Point)(p), err
 }
 
+func ToUncompressedG1(p *G1Point) []byte {
+	return kbls.NewG1().ToUncompressed((*kbls.PointG1)(p))
+}
+
+func fromUncompressedG1(v []byte) (*G1Point, error) {
+	p, err := kbls.NewG1().FromUncompressed(v)
+	return (*G1Point)(p), err
+}
+
 func ToCompressedG2(p *G2Point) []byte {
 	return kbls.NewG2().ToCompressed((*kbls.PointG2)(p))
 }
 
-func FromCompressedG2(v []byte) (*G2Point, error) {
+func fromCompressedG2(v []byte) (*G2Point, error) {
 	p, err := kbls.NewG2().FromCompressed(v)
 	return (*G2Point)(p), err
 }
 
+func ToUncompressedG2(p *G2Point) []byte {
+	return kbls.NewG2().ToUncompressed((*kbls.PointG2)(p))
+}
+
+func fromUncompressedG2(v []byte) (*G2Point, error) {
+	p, err := kbls.NewG2().FromUncompressed(v)
+	return (*G2Point)(p), err
+}
+
 func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
 	if len(numbers) != len(factors) {
 		panic("got LinCombG1 numbers/factors length mismatch")
@@ -149,6 +179,26 @@ func LinCombG1(numbers []G1Point, factors []Fr) *G1Point {
 	return &out
 }
 
+func LinCombG2(numbers []G2Point, factors []Fr) *G2Point {
+	if len(numbers) != len(factors) {
+		panic("got LinCombG2 numbers/factors length mismatch")
+	}
+	var out G2Point
+	tmpG2s := make([]*kbls.PointG2, len(numbers), len(numbers))
+	for i := 0; i < len(numbers); i++ {
+		tmpG2s[i] = (*kbls.PointG2)(&numbers[i])
+	}
+	tmpFrs := make([]*kbls.Fr, len(factors), len(factors))
+	for i := 0; i < len(factors); i++ {
+		// copy, since we have to change from mont-red form to regular again, and don't want to mutate the input.
+		v := *(*kbls.Fr)(&factors[i])
+		v.FromRed()
+		tmpFrs[i] = &v
+	}
+	_, _ = kbls.NewG2().MultiExp((*kbls.PointG2)(&out), tmpG2s, tmpFrs)
+	return &out
+}
+
 // e(a1^(-1), a2) * e(b1,  b2) = 1_T
 func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
 	pairingEngine := kbls.NewEngine()
@@ -157,6 +207,19 @@ func PairingsVerify(a1 *G1Point, a2 *G2Point, b1 *G1Point, b2 *G2Point) bool {
 	return pairingEngine.Check()
 }
 
+// PairingsCheck checks if the product of the pairings e(g1s[i], g2s[i]) equals 1_T.
+// The Miller loops of all pairs share a single final exponentiation.
+func PairingsCheck(g1s []G1Point, g2s []G2Point) bool {
+	if len(g1s) != len(g2s) {
+		panic("got PairingsCheck g1s/g2s length mismatch")
+	}
+	pairingEngine := kbls.NewEngine()
+	for i := range g1s {
+		pairingEngine.AddPair((*kbls.PointG1)(&g1s[i]), (*kbls.PointG2)(&g2s[i]))
+	}
+	return pairingEngine.Check()
+}
+
 func DebugG1s(msg string, values []G1Point) {
 	var out strings.Builder
 	for i := range values {
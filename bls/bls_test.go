@@ -76,6 +76,54 @@ func TestEmptyG1Lincomb(t *testing.T) {
 		t.Fatalf("Expected zero group element, got:\n%s", StrG1(out))
 	}
 }
+
+func TestEmptyG2Lincomb(t *testing.T) {
+	out := LinCombG2([]G2Point{}, []Fr{})
+	if out == nil {
+		t.Fatal("got nil, expected result when given 0 points and 0 scalars should be the zero group element")
+	}
+
+	if !EqualG2(out, &ZeroG2) {
+		t.Fatalf("Expected zero group element, got:\n%s", StrG2(out))
+	}
+}
+
+func TestG2Lincomb(t *testing.T) {
+	var x1, x2, x3, x4 Fr
+	SetFr(&x1, "1")
+	SetFr(&x2, "2")
+	SetFr(&x3, "3")
+	SetFr(&x4, "44689111813071777962210527909085028157792767057343609826799812096627770269092")
+	factors := []Fr{x1, x2, x3, x4}
+	points := make([]G2Point, len(factors))
+	for i := range points {
+		var s Fr
+		AsFr(&s, uint64(i*i+5))
+		MulG2(&points[i], &GenG2, &s)
+	}
+
+	// Happy path: matches the naive sum of products
+	var expected, tmp, prod G2Point
+	CopyG2(&expected, &ZeroG2)
+	for i := range points {
+		MulG2(&prod, &points[i], &factors[i])
+		CopyG2(&tmp, &expected)
+		AddG2(&expected, &tmp, &prod)
+	}
+	out := LinCombG2(points, factors)
+	if !EqualG2(out, &expected) {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", StrG2(&expected), StrG2(out))
+	}
+
+	// Error path: points and scalars not same length
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic on length mismatch, got none")
+		}
+	}()
+	LinCombG2(points, factors[:3])
+}
+
 func TestPolyLincomb(t *testing.T) {
 	var x1, x2, x3, x4 Fr
 	SetFr(&x1, "1")
@@ -118,3 +166,44 @@ func TestPolyLincomb(t *testing.T) {
 		t.Fatal("Expected error, got none")
 	}
 }
+
+func TestPairingsCheck(t *testing.T) {
+	var a, b, ab Fr
+	SetFr(&a, "44689111813071777962210527909085028157792767057343609826799812096627770269092")
+	SetFr(&b, "26444158170683616486493062254748234829545368615823006596610545696213139843950")
+	MulModFr(&ab, &a, &b)
+
+	var aG1, negAbG1, bG1 G1Point
+	MulG1(&aG1, &GenG1, &a)
+	MulG1(&negAbG1, &GenG1, &ab)
+	NegG1(&negAbG1)
+	MulG1(&bG1, &GenG1, &b)
+	var bG2, aG2 G2Point
+	MulG2(&bG2, &GenG2, &b)
+	MulG2(&aG2, &GenG2, &a)
+
+	// e(a, b) * e(-ab, 1) = 1_T
+	if !PairingsCheck([]G1Point{aG1, negAbG1}, []G2Point{bG2, GenG2}) {
+		t.Fatal("expected pairing check of 2 pairs to pass")
+	}
+	// e(a, b) * e(b, a) * e(-ab, 1) * e(-ab, 1) = 1_T
+	if !PairingsCheck([]G1Point{aG1, bG1, negAbG1, negAbG1}, []G2Point{bG2, aG2, GenG2, GenG2}) {
+		t.Fatal("expected pairing check of 4 pairs to pass")
+	}
+	// e(a, b) * e(b, a) * e(-ab, 1) != 1_T
+	if PairingsCheck([]G1Point{aG1, bG1, negAbG1}, []G2Point{bG2, aG2, GenG2}) {
+		t.Fatal("expected pairing check of 3 pairs to fail")
+	}
+	if !PairingsCheck([]G1Point{}, []G2Point{}) {
+		t.Fatal("expected empty pairing check to pass")
+	}
+	// PairingsVerify: e(a^(-1), b) * e(ab, 1) = 1_T
+	var abG1 G1Point
+	MulG1(&abG1, &GenG1, &ab)
+	if !PairingsVerify(&aG1, &bG2, &abG1, &GenG2) {
+		t.Fatal("expected pairings verify to pass")
+	}
+	if PairingsVerify(&bG1, &bG2, &abG1, &GenG2) {
+		t.Fatal("expected pairings verify to fail")
+	}
+}
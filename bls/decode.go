@@ -0,0 +1,166 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package bls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Point encodings follow the ZCash BLS12-381 serialization format: big-endian coordinates,
+// with the 3 most significant bits of the first byte used as flags.
+// The decoders apply the same rules with every backend:
+//   - the input has the exact size of the encoding, and the compression flag matches it
+//   - the point at infinity has a single canonical encoding: only the infinity (and compression) flag set, all other bits zero
+//   - the sign flag is only set in compressed encodings
+//   - every coordinate is canonical: smaller than the field modulus
+//   - the point is on the curve and in the prime order subgroup
+//
+// The point at infinity is only accepted when the caller explicitly allows it.
+const (
+	G1CompressedSize   = 48
+	G1UncompressedSize = 96
+	G2CompressedSize   = 96
+	G2UncompressedSize = 192
+)
+
+const (
+	compressionFlag = 0x80
+	infinityFlag    = 0x40
+	signFlag        = 0x20
+	flagsMask       = compressionFlag | infinityFlag | signFlag
+)
+
+// big-endian modulus of the base field Fp
+var fpModulus, _ = hex.DecodeString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+
+// Sentinel errors of the point decoders. Returned errors wrap these, use errors.Is to match them.
+var (
+	// ErrInvalidEncoding is returned when the size, the flags or a coordinate of an encoded point is not canonical.
+	ErrInvalidEncoding = errors.New("invalid point encoding")
+	// ErrInfinityNotAllowed is returned when decoding the point at infinity, where it is not allowed.
+	ErrInfinityNotAllowed = errors.New("point at infinity not allowed")
+	// ErrInvalidPoint is returned when an encoded point is not on the curve, or not in the subgroup.
+	ErrInvalidPoint = errors.New("invalid point")
+)
+
+// checkEncoding checks the size, flags and coordinates of the encoding, and returns if it encodes the point at infinity.
+func checkEncoding(v []byte, size int, compressed bool) (infinity bool, err error) {
+	if len(v) != size {
+		return false, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidEncoding, size, len(v))
+	}
+	if (v[0]&compressionFlag != 0) != compressed {
+		return false, fmt.Errorf("%w: compression flag does not match size", ErrInvalidEncoding)
+	}
+	if v[0]&infinityFlag != 0 {
+		if v[0]&^(compressionFlag|infinityFlag) != 0 {
+			return false, fmt.Errorf("%w: non-zero bits in point at infinity", ErrInvalidEncoding)
+		}
+		for _, b := range v[1:] {
+			if b != 0 {
+				return false, fmt.Errorf("%w: non-zero bits in point at infinity", ErrInvalidEncoding)
+			}
+		}
+		return true, nil
+	}
+	if !compressed && v[0]&signFlag != 0 {
+		return false, fmt.Errorf("%w: sign flag set in uncompressed point", ErrInvalidEncoding)
+	}
+	var first [G1CompressedSize]byte
+	copy(first[:], v[:G1CompressedSize])
+	first[0] &^= flagsMask
+	if bytes.Compare(first[:], fpModulus) >= 0 {
+		return false, fmt.Errorf("%w: coordinate 0 is not canonical", ErrInvalidEncoding)
+	}
+	for i := G1CompressedSize; i < size; i += G1CompressedSize {
+		if bytes.Compare(v[i:i+G1CompressedSize], fpModulus) >= 0 {
+			return false, fmt.Errorf("%w: coordinate %d is not canonical", ErrInvalidEncoding, i/G1CompressedSize)
+		}
+	}
+	return false, nil
+}
+
+func decodeG1(v []byte, size int, compressed bool, allowInfinity bool, decode func(v []byte) (*G1Point, error)) (*G1Point, error) {
+	infinity, err := checkEncoding(v, size, compressed)
+	if err != nil {
+		return nil, err
+	}
+	if infinity {
+		if !allowInfinity {
+			return nil, ErrInfinityNotAllowed
+		}
+		var out G1Point
+		ClearG1(&out)
+		return &out, nil
+	}
+	// the backend decoders check the point is on the curve and in the subgroup
+	p, err := decode(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPoint, err)
+	}
+	return p, nil
+}
+
+func decodeG2(v []byte, size int, compressed bool, allowInfinity bool, decode func(v []byte) (*G2Point, error)) (*G2Point, error) {
+	infinity, err := checkEncoding(v, size, compressed)
+	if err != nil {
+		return nil, err
+	}
+	if infinity {
+		if !allowInfinity {
+			return nil, ErrInfinityNotAllowed
+		}
+		var out G2Point
+		ClearG2(&out)
+		return &out, nil
+	}
+	// the backend decoders check the point is on the curve and in the subgroup
+	p, err := decode(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPoint, err)
+	}
+	return p, nil
+}
+
+// DecodeCompressedG1 decodes a 48 byte compressed G1 point, the point at infinity is only accepted if allowInfinity is true.
+func DecodeCompressedG1(v []byte, allowInfinity bool) (*G1Point, error) {
+	return decodeG1(v, G1CompressedSize, true, allowInfinity, fromCompressedG1)
+}
+
+// DecodeUncompressedG1 decodes a 96 byte uncompressed G1 point, the point at infinity is only accepted if allowInfinity is true.
+func DecodeUncompressedG1(v []byte, allowInfinity bool) (*G1Point, error) {
+	return decodeG1(v, G1UncompressedSize, false, allowInfinity, fromUncompressedG1)
+}
+
+// DecodeCompressedG2 decodes a 96 byte compressed G2 point, the point at infinity is only accepted if allowInfinity is true.
+func DecodeCompressedG2(v []byte, allowInfinity bool) (*G2Point, error) {
+	return decodeG2(v, G2CompressedSize, true, allowInfinity, fromCompressedG2)
+}
+
+// DecodeUncompressedG2 decodes a 192 byte uncompressed G2 point, the point at infinity is only accepted if allowInfinity is true.
+func DecodeUncompressedG2(v []byte, allowInfinity bool) (*G2Point, error) {
+	return decodeG2(v, G2UncompressedSize, false, allowInfinity, fromUncompressedG2)
+}
+
+// FromCompressedG1 decodes a compressed G1 point, like DecodeCompressedG1, accepting the point at infinity.
+func FromCompressedG1(v []byte) (*G1Point, error) {
+	return DecodeCompressedG1(v, true)
+}
+
+// FromUncompressedG1 decodes an uncompressed G1 point, like DecodeUncompressedG1, accepting the point at infinity.
+func FromUncompressedG1(v []byte) (*G1Point, error) {
+	return DecodeUncompressedG1(v, true)
+}
+
+// FromCompressedG2 decodes a compressed G2 point, like DecodeCompressedG2, accepting the point at infinity.
+func FromCompressedG2(v []byte) (*G2Point, error) {
+	return DecodeCompressedG2(v, true)
+}
+
+// FromUncompressedG2 decodes an uncompressed G2 point, like DecodeUncompressedG2, accepting the point at infinity.
+func FromUncompressedG2(v []byte) (*G2Point, error) {
+	return DecodeUncompressedG2(v, true)
+}
@@ -0,0 +1,195 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package bls
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// g1WithSmallCoordinate returns a G1 point, of which coordinate i (0 for x, 1 for y) is small enough
+// to still fit in the encoding after adding the field modulus.
+func g1WithSmallCoordinate(t *testing.T, i int) *G1Point {
+	var p G1Point
+	CopyG1(&p, &GenG1)
+	for j := 0; j < 100; j++ {
+		enc := ToUncompressedG1(&p)
+		if enc[i*G1CompressedSize] < 0x05 {
+			return &p
+		}
+		AddG1(&p, &p, &GenG1)
+	}
+	t.Fatal("no point found")
+	return nil
+}
+
+// addModulus adds the field modulus to the big-endian 48 byte coordinate, keeping the flags.
+func addModulus(coordinate []byte) {
+	flags := coordinate[0] & flagsMask
+	coordinate[0] &^= flagsMask
+	v := new(big.Int).SetBytes(coordinate)
+	v.Add(v, new(big.Int).SetBytes(fpModulus))
+	v.FillBytes(coordinate)
+	coordinate[0] |= flags
+}
+
+// g1OutsideSubgroup returns the uncompressed encoding of a point on the curve, but not in the G1 subgroup.
+func g1OutsideSubgroup(t *testing.T) []byte {
+	p := new(big.Int).SetBytes(fpModulus)
+	for x := int64(1); x < 100; x++ {
+		// y**2 = x**3 + 4
+		rhs := big.NewInt(x*x*x + 4)
+		y := new(big.Int).ModSqrt(rhs, p)
+		if y == nil {
+			continue
+		}
+		out := make([]byte, G1UncompressedSize)
+		big.NewInt(x).FillBytes(out[:G1CompressedSize])
+		y.FillBytes(out[G1CompressedSize:])
+		return out
+	}
+	t.Fatal("no point found")
+	return nil
+}
+
+func TestDecodeG1(t *testing.T) {
+	var s Fr
+	SetFr(&s, "44689111813071777962210527909085028157792767057343609826799812096627770269092")
+	var point G1Point
+	MulG1(&point, &GenG1, &s)
+	compressed := ToCompressedG1(&point)
+	uncompressed := ToUncompressedG1(&point)
+	if len(compressed) != G1CompressedSize || len(uncompressed) != G1UncompressedSize {
+		t.Fatalf("unexpected encoding sizes: %d, %d", len(compressed), len(uncompressed))
+	}
+
+	infinityCompressed := make([]byte, G1CompressedSize)
+	infinityCompressed[0] = compressionFlag | infinityFlag
+	infinityUncompressed := make([]byte, G1UncompressedSize)
+	infinityUncompressed[0] = infinityFlag
+
+	modify := func(v []byte, f func(v []byte)) []byte {
+		out := append([]byte{}, v...)
+		f(out)
+		return out
+	}
+
+	nonCanonicalX := ToCompressedG1(g1WithSmallCoordinate(t, 0))
+	addModulus(nonCanonicalX)
+	nonCanonicalY := ToUncompressedG1(g1WithSmallCoordinate(t, 1))
+	addModulus(nonCanonicalY[G1CompressedSize:])
+	outsideSubgroup := g1OutsideSubgroup(t)
+
+	cases := []struct {
+		name       string
+		enc        []byte
+		infinityOk bool
+		err        error
+		expected   *G1Point
+	}{
+		{"compressed", compressed, false, nil, &point},
+		{"uncompressed", uncompressed, false, nil, &point},
+		{"compressed infinity", infinityCompressed, true, nil, &ZeroG1},
+		{"uncompressed infinity", infinityUncompressed, true, nil, &ZeroG1},
+		{"compressed infinity not allowed", infinityCompressed, false, ErrInfinityNotAllowed, nil},
+		{"uncompressed infinity not allowed", infinityUncompressed, false, ErrInfinityNotAllowed, nil},
+		{"infinity with sign", modify(infinityCompressed, func(v []byte) { v[0] |= signFlag }), true, ErrInvalidEncoding, nil},
+		{"infinity with non-zero x", modify(infinityCompressed, func(v []byte) { v[47] = 1 }), true, ErrInvalidEncoding, nil},
+		{"infinity with non-zero y", modify(infinityUncompressed, func(v []byte) { v[95] = 1 }), true, ErrInvalidEncoding, nil},
+		{"short", compressed[:47], false, ErrInvalidEncoding, nil},
+		{"long", append(append([]byte{}, compressed...), 0), false, ErrInvalidEncoding, nil},
+		{"missing compression flag", modify(compressed, func(v []byte) { v[0] &^= compressionFlag }), false, ErrInvalidEncoding, nil},
+		{"unexpected compression flag", modify(uncompressed, func(v []byte) { v[0] |= compressionFlag }), false, ErrInvalidEncoding, nil},
+		{"uncompressed with sign", modify(uncompressed, func(v []byte) { v[0] |= signFlag }), false, ErrInvalidEncoding, nil},
+		{"non-canonical x", nonCanonicalX, false, ErrInvalidEncoding, nil},
+		{"non-canonical y", nonCanonicalY, false, ErrInvalidEncoding, nil},
+		{"not on curve", modify(uncompressed, func(v []byte) { v[95] ^= 1 }), false, ErrInvalidPoint, nil},
+		{"outside subgroup", outsideSubgroup, false, ErrInvalidPoint, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var p *G1Point
+			var err error
+			if len(c.enc) > 0 && c.enc[0]&compressionFlag != 0 {
+				p, err = DecodeCompressedG1(c.enc, c.infinityOk)
+			} else {
+				p, err = DecodeUncompressedG1(c.enc, c.infinityOk)
+			}
+			if c.err != nil {
+				if !errors.Is(err, c.err) {
+					t.Fatalf("expected error %v, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !EqualG1(p, c.expected) {
+				t.Fatalf("decoded point does not match:\n%s\n%s", StrG1(p), StrG1(c.expected))
+			}
+		})
+	}
+}
+
+func TestDecodeG2(t *testing.T) {
+	var s Fr
+	SetFr(&s, "44689111813071777962210527909085028157792767057343609826799812096627770269092")
+	var point G2Point
+	MulG2(&point, &GenG2, &s)
+	compressed := ToCompressedG2(&point)
+	uncompressed := ToUncompressedG2(&point)
+	if len(compressed) != G2CompressedSize || len(uncompressed) != G2UncompressedSize {
+		t.Fatalf("unexpected encoding sizes: %d, %d", len(compressed), len(uncompressed))
+	}
+	for _, enc := range [][]byte{compressed, uncompressed} {
+		var p *G2Point
+		var err error
+		if len(enc) == G2CompressedSize {
+			p, err = DecodeCompressedG2(enc, false)
+		} else {
+			p, err = DecodeUncompressedG2(enc, false)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !EqualG2(p, &point) {
+			t.Fatalf("decoded point does not match:\n%s\n%s", StrG2(p), StrG2(&point))
+		}
+	}
+
+	infinity := make([]byte, G2CompressedSize)
+	infinity[0] = compressionFlag | infinityFlag
+	if _, err := DecodeCompressedG2(infinity, false); !errors.Is(err, ErrInfinityNotAllowed) {
+		t.Fatalf("expected infinity to be rejected, got %v", err)
+	}
+	if p, err := FromCompressedG2(infinity); err != nil || !EqualG2(p, &ZeroG2) {
+		t.Fatalf("expected infinity to be accepted, got %v", err)
+	}
+
+	// every coordinate is checked: the x.c0 of the compressed form, and the y.c1 of the uncompressed form
+	for _, i := range []int{1, 2} {
+		enc := compressed
+		if i > 1 {
+			enc = uncompressed
+		}
+		enc = append([]byte{}, enc...)
+		copy(enc[i*G1CompressedSize:(i+1)*G1CompressedSize], fpModulus)
+		var err error
+		if i > 1 {
+			_, err = DecodeUncompressedG2(enc, false)
+		} else {
+			_, err = DecodeCompressedG2(enc, false)
+		}
+		if !errors.Is(err, ErrInvalidEncoding) {
+			t.Fatalf("coordinate %d: expected non-canonical coordinate to be rejected, got %v", i, err)
+		}
+	}
+
+	notOnCurve := append([]byte{}, uncompressed...)
+	notOnCurve[G2UncompressedSize-1] ^= 1
+	if _, err := DecodeUncompressedG2(notOnCurve, false); !errors.Is(err, ErrInvalidPoint) {
+		t.Fatalf("expected point not on curve to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package bls
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+)
+
+func g1Hex(p *G1Point) string {
+	return hex.EncodeToString(ToCompressedG1(p))
+}
+
+func g2Hex(p *G2Point) string {
+	return hex.EncodeToString(ToCompressedG2(p))
+}
+
+func TestDifferentialBLS(t *testing.T) {
+	r := &diffRand{seed: "go-kzg differential bls"}
+	var lines goldenLines
+	const n = 16
+
+	randomG1 := func() (G1Point, Fr) {
+		s := r.fr()
+		var p G1Point
+		MulG1(&p, &GenG1, &s)
+		return p, s
+	}
+
+	for i := 0; i < n; i++ {
+		p, a := randomG1()
+		q, b := randomG1()
+		var add, sub, neg G1Point
+		AddG1(&add, &p, &q)
+		SubG1(&sub, &p, &q)
+		CopyG1(&neg, &p)
+		NegG1(&neg)
+		lines.add("g1mul", frHex(&a), g1Hex(&p))
+		lines.add("g1add", frHex(&a), frHex(&b), g1Hex(&add))
+		lines.add("g1sub", frHex(&a), frHex(&b), g1Hex(&sub))
+		lines.add("g1neg", frHex(&a), g1Hex(&neg))
+		lines.add("g1eq", frHex(&a), frHex(&b), strconv.FormatBool(EqualG1(&p, &q)), strconv.FormatBool(IsValidG1(&p)))
+	}
+
+	for i := 0; i < n; i++ {
+		s := r.fr()
+		var p G2Point
+		MulG2(&p, &GenG2, &s)
+		lines.add("g2mul", frHex(&s), g2Hex(&p))
+	}
+
+	for i := 0; i < n/4; i++ {
+		points := make([]G1Point, i*3)
+		factors := make([]Fr, len(points))
+		values := make([]string, 0, len(points)+1)
+		for j := range points {
+			points[j], _ = randomG1()
+			factors[j] = r.fr()
+			values = append(values, frHex(&factors[j]))
+		}
+		values = append(values, g1Hex(LinCombG1(points, factors)))
+		lines.add("lincomb", values...)
+	}
+
+	// decompression: valid points round-trip, and every backend rejects the same invalid encodings
+	var encodings [][]byte
+	for i := 0; i < n; i++ {
+		p, _ := randomG1()
+		encodings = append(encodings, ToCompressedG1(&p))
+	}
+	infinity := make([]byte, 48)
+	infinity[0] = 0xc0
+	encodings = append(encodings, infinity)
+	badInfinity := make([]byte, 48)
+	copy(badInfinity, infinity)
+	badInfinity[47] = 1
+	uncompressedFlag := make([]byte, 48)
+	copy(uncompressedFlag, encodings[0])
+	uncompressedFlag[0] &^= 0x80
+	xTooLarge := make([]byte, 48)
+	for i := range xTooLarge {
+		xTooLarge[i] = 0xff
+	}
+	xTooLarge[0] = 0x9f
+	encodings = append(encodings, badInfinity, uncompressedFlag, xTooLarge, encodings[0][:47])
+	for i := 0; i < n; i++ {
+		// random x coordinates: about half are not on the curve, and the rest are not in the subgroup
+		b1, b2 := r.bytes32(), r.bytes32()
+		enc := append(b1[:], b2[:16]...)
+		enc[0] = 0x80 | (enc[0] & 0x1f)
+		encodings = append(encodings, enc)
+	}
+	for _, enc := range encodings {
+		if p, err := FromCompressedG1(enc); err != nil {
+			lines.add("g1decompress", hex.EncodeToString(enc), "invalid")
+		} else {
+			lines.add("g1decompress", hex.EncodeToString(enc), "ok", g1Hex(p))
+		}
+	}
+
+	// uncompressed encodings, including the point at infinity
+	for i := 0; i < n/4; i++ {
+		s := r.fr()
+		var p G1Point
+		var q G2Point
+		MulG1(&p, &GenG1, &s)
+		MulG2(&q, &GenG2, &s)
+		lines.add("uncompressed", frHex(&s), hex.EncodeToString(ToUncompressedG1(&p)), hex.EncodeToString(ToUncompressedG2(&q)))
+	}
+	lines.add("uncompressed", "infinity", hex.EncodeToString(ToUncompressedG1(&ZeroG1)), hex.EncodeToString(ToUncompressedG2(&ZeroG2)))
+
+	for i := 0; i < n/4; i++ {
+		// e([a]G1, [b]G2) == e([a*b]G1, G2), and not for a different product
+		a, b := r.nonZeroFr(), r.nonZeroFr()
+		var ab Fr
+		MulModFr(&ab, &a, &b)
+		var a1, ab1, wrong1 G1Point
+		var b2 G2Point
+		MulG1(&a1, &GenG1, &a)
+		MulG1(&ab1, &GenG1, &ab)
+		AddG1(&wrong1, &ab1, &GenG1)
+		MulG2(&b2, &GenG2, &b)
+		lines.add("pairing", frHex(&a), frHex(&b),
+			strconv.FormatBool(PairingsVerify(&ab1, &GenG2, &a1, &b2)),
+			strconv.FormatBool(PairingsVerify(&wrong1, &GenG2, &a1, &b2)))
+	}
+
+	checkGolden(t, "bls", lines)
+}
@@ -0,0 +1,237 @@
+package bls
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// The differential tests run a deterministic sequence of operations, and compare the canonical outputs
+// against golden files, so that every backend (build tag) is checked to agree bit-for-bit.
+// Regenerate the golden files, with a backend that is trusted, with:
+//
+//	go test ./bls -run TestDifferential -update
+var updateGolden = flag.Bool("update", false, "rewrite the differential golden files instead of comparing against them")
+
+// diffRand is a deterministic source of inputs: the same on every platform and backend.
+type diffRand struct {
+	seed    string
+	counter uint64
+}
+
+func (r *diffRand) bytes32() (out [32]byte) {
+	h := sha256.New()
+	h.Write([]byte(r.seed))
+	var c [8]byte
+	binary.LittleEndian.PutUint64(c[:], r.counter)
+	h.Write(c[:])
+	r.counter++
+	copy(out[:], h.Sum(nil))
+	return
+}
+
+func (r *diffRand) uint64() uint64 {
+	b := r.bytes32()
+	return binary.LittleEndian.Uint64(b[:8])
+}
+
+// fr returns a random field element, with a bias towards edge cases.
+func (r *diffRand) fr() (out Fr) {
+	b := r.bytes32()
+	switch b[0] % 16 {
+	case 0:
+		CopyFr(&out, &ZERO)
+	case 1:
+		CopyFr(&out, &ONE)
+	case 2:
+		CopyFr(&out, &MODULUS_MINUS1)
+	case 3:
+		CopyFr(&out, &MODULUS_MINUS2)
+	case 4:
+		AsFr(&out, binary.LittleEndian.Uint64(b[8:16]))
+	default:
+		b[31] &= 0x3f // below 2**254, always in range
+		if !FrFrom32(&out, b) {
+			panic("expected value in range")
+		}
+	}
+	return
+}
+
+// nonZeroFr is like fr, but never zero, for inputs of division and inversion, where zero is undefined.
+func (r *diffRand) nonZeroFr() Fr {
+	for {
+		if v := r.fr(); !EqualZero(&v) {
+			return v
+		}
+	}
+}
+
+func frHex(v *Fr) string {
+	b := FrTo32(v)
+	return hex.EncodeToString(b[:])
+}
+
+// goldenLines collects the canonical output of the operations, one line per operation.
+type goldenLines []string
+
+func (g *goldenLines) add(op string, values ...string) {
+	*g = append(*g, op+" "+strings.Join(values, " "))
+}
+
+func checkGolden(t *testing.T, name string, lines goldenLines) {
+	path := filepath.Join("testdata", "differential", name+".txt")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var expected []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		expected = append(expected, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(expected) != len(lines) {
+		t.Errorf("expected %d operations in %s, got %d", len(expected), path, len(lines))
+	}
+	mismatches := 0
+	for i := 0; i < len(expected) && i < len(lines); i++ {
+		if expected[i] != lines[i] {
+			t.Errorf("%s:%d mismatch\nexpected: %s\ngot:      %s", path, i+1, expected[i], lines[i])
+			if mismatches++; mismatches >= 10 {
+				t.Fatal("too many mismatches")
+			}
+		}
+	}
+}
+
+func TestDifferentialFr(t *testing.T) {
+	r := &diffRand{seed: "go-kzg differential fr"}
+	var lines goldenLines
+	const n = 64
+
+	for i := 0; i < n; i++ {
+		a, b := r.fr(), r.fr()
+		var add, sub, mul Fr
+		AddModFr(&add, &a, &b)
+		SubModFr(&sub, &a, &b)
+		MulModFr(&mul, &a, &b)
+		lines.add("add", frHex(&a), frHex(&b), frHex(&add))
+		lines.add("sub", frHex(&a), frHex(&b), frHex(&sub))
+		lines.add("mul", frHex(&a), frHex(&b), frHex(&mul))
+	}
+	for i := 0; i < n; i++ {
+		a, b := r.fr(), r.nonZeroFr()
+		var div, inv Fr
+		DivModFr(&div, &a, &b)
+		InvModFr(&inv, &b)
+		lines.add("div", frHex(&a), frHex(&b), frHex(&div))
+		lines.add("inv", frHex(&b), frHex(&inv))
+	}
+	for i := 0; i < n; i++ {
+		a := r.fr()
+		var e big.Int
+		switch i % 3 {
+		case 0:
+			e.SetUint64(uint64(i))
+		case 1:
+			e.SetUint64(r.uint64())
+		default:
+			eb := r.bytes32()
+			e.SetBytes(eb[:])
+		}
+		var out Fr
+		ExpModFr(&out, &a, &e)
+		lines.add("exp", frHex(&a), e.Text(16), frHex(&out))
+	}
+	for i := 0; i < n; i++ {
+		a := r.fr()
+		lines.add("str", frHex(&a), FrStr(&a))
+		lines.add("eq", frHex(&a), strconv.FormatBool(EqualZero(&a)), strconv.FormatBool(EqualOne(&a)))
+	}
+
+	// FrFrom32 edge cases: the modulus and anything above is rejected
+	modulusLE, _ := new(big.Int).SetString(ModulusStr, 10)
+	leBytes := func(v *big.Int) (out [32]byte) {
+		var be [32]byte
+		v.FillBytes(be[:])
+		for i := 0; i < 32; i++ {
+			out[i] = be[31-i]
+		}
+		return
+	}
+	one := big.NewInt(1)
+	inputs := [][32]byte{
+		{},
+		{0: 1},
+		leBytes(new(big.Int).Sub(modulusLE, one)),
+		leBytes(modulusLE),
+		leBytes(new(big.Int).Add(modulusLE, one)),
+		leBytes(new(big.Int).Sub(new(big.Int).Lsh(one, 256), one)),
+		{31: 0x73},
+		{31: 0x74},
+	}
+	for i := 0; i < n; i++ {
+		inputs = append(inputs, r.bytes32()) // about half of these are out of range
+	}
+	for _, in := range inputs {
+		var out Fr
+		if FrFrom32(&out, in) {
+			lines.add("from32", hex.EncodeToString(in[:]), "ok", frHex(&out))
+		} else {
+			lines.add("from32", hex.EncodeToString(in[:]), "invalid")
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		poly := make([]Fr, i%9)
+		values := make([]string, 0, len(poly)+2)
+		for j := range poly {
+			poly[j] = r.fr()
+			values = append(values, frHex(&poly[j]))
+		}
+		x := r.fr()
+		var y Fr
+		EvalPolyAt(&y, poly, &x)
+		values = append(values, frHex(&x), frHex(&y))
+		lines.add("evalpoly", values...)
+	}
+
+	for i := 0; i < n/8; i++ {
+		values := make([]Fr, 8)
+		for j := range values {
+			values[j] = r.nonZeroFr()
+		}
+		outputs := make([]string, 0, 16)
+		for j := range values {
+			outputs = append(outputs, frHex(&values[j]))
+		}
+		BatchInvModFr(values)
+		for j := range values {
+			outputs = append(outputs, frHex(&values[j]))
+		}
+		lines.add("batchinv", outputs...)
+	}
+
+	checkGolden(t, "fr", lines)
+}
@@ -35,3 +35,33 @@ func benchCommit(scale uint8, b *testing.B) {
 		bls.LinCombG1(setupLagrange, blob)
 	}
 }
+
+func BenchmarkComputeProof(b *testing.B) {
+	scale := uint8(12)
+	fs := NewFFTSettings(scale)
+	setupG1, setupG2 := GenerateTestingSetup("1234", uint64(1)<<scale+1)
+	ks := NewKZGSettings(fs, setupG1, setupG2)
+	poly := make([]bls.Fr, uint64(1)<<scale)
+	for i := 0; i < len(poly); i++ {
+		poly[i] = *bls.RandomFr()
+	}
+	b.Run("single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ks.ComputeProofSingle(poly, 17)
+		}
+	})
+	b.Run("multi_16", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ks.ComputeProofMulti(poly, 17, 16)
+		}
+	})
+	xs := make([]bls.Fr, 256)
+	for i := range xs {
+		bls.AsFr(&xs[i], uint64(i*7+1))
+	}
+	b.Run("points_256", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ks.ComputeProofMultiPoints(poly, xs)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// EvalUpdate describes a change of a single evaluation of a polynomial in evaluation form:
+// the value at Index, over the roots of unity in natural order, changes from Old to New.
+type EvalUpdate struct {
+	Index uint64
+	Old   bls.Fr
+	New   bls.Fr
+}
+
+// evalUpdateDeltas returns New - Old for each update, and checks the indices are within the domain of the given width
+func evalUpdateDeltas(updates []EvalUpdate, width uint64) ([]bls.Fr, error) {
+	deltas := make([]bls.Fr, len(updates), len(updates))
+	for i := range updates {
+		if updates[i].Index >= width {
+			return nil, fmt.Errorf("%w: update %d has index %d, domain width is %d",
+				ErrInvalidIndex, i, updates[i].Index, width)
+		}
+		bls.SubModFr(&deltas[i], &updates[i].New, &updates[i].Old)
+	}
+	return deltas, nil
+}
+
+// UpdateCommitment updates a commitment to a polynomial in evaluation form of the given width (see CommitToEvalPoly),
+// without recommitting to the full polynomial: each update adds (New - Old) * [L_i(s)]_1,
+// for the Lagrange basis polynomial L_i of the updated index. The cost is a single MSM over the updates.
+// It panics on invalid inputs, see TryUpdateCommitment for an error-returning variant.
+func (ks *KZGSettings) UpdateCommitment(commitment *bls.G1Point, width uint64, updates []EvalUpdate) *bls.G1Point {
+	out, err := ks.TryUpdateCommitment(commitment, width, updates)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryUpdateCommitment is the error-returning variant of UpdateCommitment
+func (ks *KZGSettings) TryUpdateCommitment(commitment *bls.G1Point, width uint64, updates []EvalUpdate) (*bls.G1Point, error) {
+	lagrange, err := ks.LagrangeG1(width)
+	if err != nil {
+		return nil, err
+	}
+	deltas, err := evalUpdateDeltas(updates, width)
+	if err != nil {
+		return nil, err
+	}
+	var out bls.G1Point
+	bls.CopyG1(&out, commitment)
+	if len(updates) == 0 {
+		return &out, nil
+	}
+	// copies, the shared Lagrange points must not be modified by the MSM
+	points := make([]bls.G1Point, len(updates), len(updates))
+	for i := range updates {
+		bls.CopyG1(&points[i], &lagrange[updates[i].Index])
+	}
+	bls.AddG1(&out, commitment, bls.LinCombG1(points, deltas))
+	return &out, nil
+}
+
+// updateBasesCache holds the update bases of FK20SingleSettings. It is allocated separately from the settings,
+// so copies of the settings share it, instead of copying the lock.
+type updateBasesCache struct {
+	lock  sync.Mutex
+	bases []bls.G1Point
+}
+
+// getUpdateBases returns the update bases, from the cache if the settings have one.
+func (fk *FK20SingleSettings) getUpdateBases() ([]bls.G1Point, error) {
+	c := fk.updateBases
+	if c == nil {
+		return fk.deriveUpdateBases()
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.bases != nil {
+		return c.bases, nil
+	}
+	bases, err := fk.deriveUpdateBases()
+	if err != nil {
+		return nil, err
+	}
+	c.bases = bases
+	return bases, nil
+}
+
+// deriveUpdateBases computes, for each point z_j = w^j of the extended domain of size 2n,
+// the commitment to C_j(X) = (X^n - z_j^n)/(X - z_j) = sum_k z_j^(n-1-k) X^k.
+// All of them come from a single FFT over G1 of the zero-padded setup.
+func (fk *FK20SingleSettings) deriveUpdateBases() ([]bls.G1Point, error) {
+	n2 := uint64(len(fk.xExtFFT))
+	n := n2 / 2
+	padded := make([]bls.G1Point, n2, n2)
+	for i := uint64(0); i < n; i++ {
+		bls.CopyG1(&padded[i], &fk.SecretG1[i])
+	}
+	for i := n; i < n2; i++ {
+		bls.CopyG1(&padded[i], &bls.ZeroG1)
+	}
+	// sums[j] = sum_k [s^k] w^(jk)
+	sums, err := fk.FFTG1(padded, false)
+	if err != nil {
+		return nil, err
+	}
+	stride := fk.MaxWidth / n2
+	out := make([]bls.G1Point, n2, n2)
+	for j := uint64(0); j < n2; j++ {
+		// sum_k [s^k] z_j^(-k) is found at index -j, then scale by z_j^(n-1)
+		bls.MulG1(&out[j], &sums[(n2-j)%n2], &fk.ExpandedRootsOfUnity[((j*(n-1))%n2)*stride])
+	}
+	return out, nil
+}
+
+// UpdateDAProofs updates, in-place, the proofs computed with DAUsingFK20 for a polynomial of n coefficients,
+// to match the polynomial after applying the evaluation updates.
+// The updates are over the n-th roots of unity, in natural order, i.e. the polynomial in evaluation form.
+//
+// With the Lagrange basis L_i(X) = (w_i / n) (X^n - 1)/(X - w_i), the proof at a point z != w_i changes by
+//
+//	delta * [(L_i(X) - L_i(z))/(X - z)] = delta * (w_i / n) / (w_i - z) * ([C_(w_i)(s)] - [C_z(s)])
+//
+// where C_z(X) = (X^n - z^n)/(X - z). These are precomputed for all 2n points, once per settings.
+// The proof at w_i itself changes by delta * [(L_i(X) - 1)/(X - w_i)], which costs an MSM of n points per update.
+// Each proof is then updated with an MSM of the size of the updates, so this is only worth it for a small
+// number of updates, compared to recomputing all proofs.
+// It panics on invalid inputs, see TryUpdateDAProofs for an error-returning variant.
+func (fk *FK20SingleSettings) UpdateDAProofs(proofs []bls.G1Point, updates []EvalUpdate) {
+	if err := fk.TryUpdateDAProofs(proofs, updates); err != nil {
+		panic(err)
+	}
+}
+
+// TryUpdateDAProofs is the error-returning variant of UpdateDAProofs.
+// The proofs are left untouched if an error is returned.
+func (fk *FK20SingleSettings) TryUpdateDAProofs(proofs []bls.G1Point, updates []EvalUpdate) error {
+	n2 := uint64(len(fk.xExtFFT))
+	n := n2 / 2
+	if uint64(len(proofs)) != n2 {
+		return fmt.Errorf("%w: expected %d proofs to match FK20-single settings, got %d", ErrLengthMismatch, n2, len(proofs))
+	}
+	deltas, err := evalUpdateDeltas(updates, n)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	bases, err := fk.getUpdateBases()
+	if err != nil {
+		return err
+	}
+
+	stride := fk.MaxWidth / n2
+	var invN bls.Fr
+	bls.AsFr(&invN, n)
+	bls.InvModFr(&invN, &invN)
+
+	// diag[u] = [(L_i(X) - 1)/(X - w_i)]_1 for the index i of update u
+	diag := make([]bls.G1Point, len(updates), len(updates))
+	lagrangePoly := make(Poly, n, n)
+	for u := range updates {
+		i := updates[u].Index
+		// L_i(X) = (1/n) sum_k w_i^(-k) X^k
+		for k := uint64(0); k < n; k++ {
+			bls.MulModFr(&lagrangePoly[k], &fk.ReverseRootsOfUnity[((2*i*k)%n2)*stride], &invN)
+		}
+		bls.SubModFr(&lagrangePoly[0], &lagrangePoly[0], &bls.ONE)
+		quotient := lagrangePoly.DivLinear(&fk.ExpandedRootsOfUnity[2*i*stride])
+		bls.CopyG1(&diag[u], bls.LinCombG1(fk.SecretG1[:len(quotient)], quotient))
+	}
+
+	// coeffs[u*n2+j] = delta_u * (w_i / n) / (w_i - z_j), for the points z_j other than w_i
+	coeffs := make([]bls.Fr, uint64(len(updates))*n2, uint64(len(updates))*n2)
+	for u := range updates {
+		wi := &fk.ExpandedRootsOfUnity[2*updates[u].Index*stride]
+		for j := uint64(0); j < n2; j++ {
+			c := &coeffs[uint64(u)*n2+j]
+			if j == 2*updates[u].Index {
+				bls.CopyFr(c, &bls.ONE)
+			} else {
+				bls.SubModFr(c, wi, &fk.ExpandedRootsOfUnity[j*stride])
+			}
+		}
+	}
+	bls.BatchInvModFr(coeffs)
+	var scale bls.Fr
+	for u := range updates {
+		bls.MulModFr(&scale, &fk.ExpandedRootsOfUnity[2*updates[u].Index*stride], &invN)
+		bls.MulModFr(&scale, &scale, &deltas[u])
+		for j := uint64(0); j < n2; j++ {
+			c := &coeffs[uint64(u)*n2+j]
+			bls.MulModFr(c, c, &scale)
+		}
+	}
+
+	points := make([]bls.G1Point, 0, len(updates)+1)
+	factors := make([]bls.Fr, 0, len(updates)+1)
+	var sum, tmp bls.Fr
+	for r := uint64(0); r < n2; r++ {
+		// the proofs are in reverse bit order
+		j := uint64(reverseBitsLimited(uint32(n2), uint32(r)))
+		points, factors = points[:0], factors[:0]
+		bls.CopyFr(&sum, &bls.ZERO)
+		for u := range updates {
+			points = append(points, bls.G1Point{})
+			factors = append(factors, bls.Fr{})
+			if j == 2*updates[u].Index {
+				bls.CopyG1(&points[len(points)-1], &diag[u])
+				bls.CopyFr(&factors[len(factors)-1], &deltas[u])
+			} else {
+				c := &coeffs[uint64(u)*n2+j]
+				bls.CopyG1(&points[len(points)-1], &bases[2*updates[u].Index])
+				bls.CopyFr(&factors[len(factors)-1], c)
+				bls.AddModFr(&sum, &sum, c)
+			}
+		}
+		points = append(points, bls.G1Point{})
+		factors = append(factors, bls.Fr{})
+		bls.CopyG1(&points[len(points)-1], &bases[j])
+		bls.SubModFr(&tmp, &bls.ZERO, &sum)
+		bls.CopyFr(&factors[len(factors)-1], &tmp)
+
+		var proof bls.G1Point
+		bls.AddG1(&proof, &proofs[r], bls.LinCombG1(points, factors))
+		bls.CopyG1(&proofs[r], &proof)
+	}
+	return nil
+}
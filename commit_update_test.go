@@ -0,0 +1,119 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestKZGSettings_UpdateCommitment(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+
+	eval := make([]bls.Fr, 16)
+	for i := range eval {
+		bls.AsFr(&eval[i], uint64(i*i+3))
+	}
+	commitment := ks.CommitToEvalPoly(eval)
+	updates := []EvalUpdate{{Index: 3}, {Index: 11}, {Index: 0}}
+	for i := range updates {
+		u := &updates[i]
+		bls.CopyFr(&u.Old, &eval[u.Index])
+		bls.AsFr(&u.New, uint64(1000+i))
+		bls.CopyFr(&eval[u.Index], &u.New)
+	}
+	updated := ks.UpdateCommitment(commitment, 16, updates)
+	if expected := ks.CommitToEvalPoly(eval); !bls.EqualG1(updated, expected) {
+		t.Fatalf("expected updated commitment %s, got %s", expected, updated)
+	}
+	if unchanged := ks.UpdateCommitment(updated, 16, nil); !bls.EqualG1(unchanged, updated) {
+		t.Fatal("expected commitment to be unchanged without updates")
+	}
+
+	if _, err := ks.TryUpdateCommitment(commitment, 16, []EvalUpdate{{Index: 16}}); !errors.Is(err, ErrInvalidIndex) {
+		t.Fatalf("expected invalid index error, got: %v", err)
+	}
+	if _, err := ks.TryUpdateCommitment(commitment, 32, updates); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+}
+
+func TestFK20SingleSettings_UpdateDAProofs(t *testing.T) {
+	for _, scale := range []uint8{5, 6} {
+		t.Run(fmt.Sprintf("scale_%d", scale), func(t *testing.T) {
+			fs := NewFFTSettings(scale)
+			s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", fs.MaxWidth+1)
+			ks := NewKZGSettings(fs, s1, s2)
+			fk := NewFK20SingleSettings(ks, 32)
+
+			polynomial := testPoly(1, 2, 3, 4, 7, 7, 7, 7, 13, 13, 13, 13, 13, 13, 13, 13)
+			eval, err := fs.FFT(polynomial, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofs := fk.DAUsingFK20(polynomial)
+
+			updates := []EvalUpdate{{Index: 1}, {Index: 6}, {Index: 15}}
+			for i := range updates {
+				u := &updates[i]
+				bls.CopyFr(&u.Old, &eval[u.Index])
+				bls.AsFr(&u.New, uint64(42+i))
+				bls.CopyFr(&eval[u.Index], &u.New)
+			}
+			fk.UpdateDAProofs(proofs, updates)
+
+			updatedPoly, err := fs.FFT(eval, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := fk.DAUsingFK20(updatedPoly)
+			for i := range expected {
+				if !bls.EqualG1(&proofs[i], &expected[i]) {
+					t.Fatalf("proof %d: expected %s, got %s", i, bls.StrG1(&expected[i]), bls.StrG1(&proofs[i]))
+				}
+			}
+
+			// and the updated proofs verify against the updated commitment
+			commitment := ks.UpdateCommitment(ks.CommitToPoly(polynomial), 16, updates)
+			pos := uint64(2)
+			x := &fs.ExpandedRootsOfUnity[pos*(fs.MaxWidth/32)]
+			var y bls.Fr
+			bls.EvalPolyAt(&y, updatedPoly, x)
+			if !ks.CheckProofSingle(commitment, &proofs[reverseBitsLimited(32, uint32(pos))], x, &y) {
+				t.Fatal("could not verify updated proof")
+			}
+
+			// copies of the settings share the cached bases, settings without a cache derive them again
+			fkCopy := *fk
+			bases, err := fkCopy.getUpdateBases()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if &bases[0] != &fk.updateBases.bases[0] {
+				t.Fatal("expected the copy to share the cached update bases")
+			}
+			uncached, err := (&FK20SingleSettings{KZGSettings: ks, xExtFFT: fk.xExtFFT}).getUpdateBases()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range bases {
+				if !bls.EqualG1(&bases[i], &uncached[i]) {
+					t.Fatalf("uncached update base %d does not match", i)
+				}
+			}
+
+			if err := fk.TryUpdateDAProofs(proofs[:16], updates); !errors.Is(err, ErrLengthMismatch) {
+				t.Fatalf("expected length mismatch error, got: %v", err)
+			}
+			if err := fk.TryUpdateDAProofs(proofs, []EvalUpdate{{Index: 16}}); !errors.Is(err, ErrInvalidIndex) {
+				t.Fatalf("expected invalid index error, got: %v", err)
+			}
+		})
+	}
+}
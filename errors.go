@@ -0,0 +1,32 @@
+package kzg
+
+import "errors"
+
+// Sentinel errors returned by the error-returning (Try*) variants of the settings constructors,
+// provers, verifiers and recovery functions. Returned errors wrap these, use errors.Is to match them.
+var (
+	// ErrNotPowerOfTwo is returned when a size or input length is required to be a power of two.
+	ErrNotPowerOfTwo = errors.New("not a power of two")
+	// ErrDomainTooSmall is returned when the FFT domain or the setup is too small for the requested size.
+	ErrDomainTooSmall = errors.New("domain too small")
+	// ErrLengthMismatch is returned when two inputs are expected to be of matching length, but are not.
+	ErrLengthMismatch = errors.New("length mismatch")
+	// ErrSizeTooSmall is returned when a size or input length is below the supported minimum.
+	ErrSizeTooSmall = errors.New("size too small")
+	// ErrSizeTooLarge is returned when a size or input length is above the supported maximum.
+	ErrSizeTooLarge = errors.New("size too large")
+	// ErrNonZeroPadding is returned when the zero-padded half of an input is not actually zero.
+	ErrNonZeroPadding = errors.New("expected zero padding")
+	// ErrInvalidIndex is returned when an index is out of range of the domain.
+	ErrInvalidIndex = errors.New("invalid index")
+	// ErrInvalidSetup is returned when a trusted setup is not consistent with a single secret.
+	ErrInvalidSetup = errors.New("invalid setup")
+	// ErrDuplicatePoint is returned when a set of evaluation points contains the same point more than once.
+	ErrDuplicatePoint = errors.New("duplicate point")
+	// ErrZeroDivisor is returned when dividing by the zero polynomial.
+	ErrZeroDivisor = errors.New("zero divisor")
+	// ErrInvalidZeroPoly is returned when a zero polynomial used for recovery does not vanish exactly at the missing samples.
+	ErrInvalidZeroPoly = errors.New("invalid zero polynomial")
+	// ErrInvalidEncoding is returned when binary encoded settings are malformed, corrupted or of an unsupported version.
+	ErrInvalidEncoding = errors.New("invalid encoding")
+)
@@ -0,0 +1,283 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package eth
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Context holds a loaded trusted setup, and everything derived from it, to run the EIP-4844 and EIP-7594 functions.
+// The width of the setup, i.e. the number of field elements per blob, is configurable:
+// mainnet uses FieldElementsPerBlob, devnets and tests may use a smaller setup.
+//
+// A Context is safe for concurrent use.
+type Context struct {
+	fieldElementsPerBlob uint64
+
+	// roots of unity of the blob domain, in bit-reversed order
+	domain []bls.Fr
+
+	// KZG CRS for G1 in monomial form, only used for cell proofs (may be nil if the setup did not include it)
+	setupG1 []bls.G1Point
+	// KZG CRS for G1 in Lagrange form, in bit-reversed order to match the domain, used for commitment computation
+	setupLagrange []bls.G1Point
+	// KZG CRS for G2 in monomial form
+	setupG2 []bls.G2Point
+
+	precompileReturnValue [64]byte
+
+	peerDASOnce     sync.Once
+	peerDASSettings *kzg.FK20MultiSettings
+	peerDASErr      error
+}
+
+// NewContext creates a context from a trusted setup. The width is the number of Lagrange points,
+// which must be a power of two. The Lagrange points are in natural order, like published by the ceremony.
+// The monomial G1 points are optional (nil), and only needed for cell proofs.
+// The Lagrange points are optional too (nil), then they are derived from the monomial G1 points.
+// The G2 points must at least include [1]_2 and [s]_2, and [s^FieldElementsPerCell]_2 for cell proofs.
+func NewContext(setupG1 []bls.G1Point, setupLagrange []bls.G1Point, setupG2 []bls.G2Point) (*Context, error) {
+	width := uint64(len(setupLagrange))
+	if setupLagrange == nil {
+		width = uint64(len(setupG1))
+	}
+	if !isPowerOfTwo(width) {
+		return nil, fmt.Errorf("setup width must be a power of two, got %d Lagrange points", width)
+	}
+	if width > (uint64(1) << (len(bls.Scale2RootOfUnity) - 1)) {
+		return nil, fmt.Errorf("setup width %d is too large", width)
+	}
+	if setupG1 != nil && uint64(len(setupG1)) != width {
+		return nil, fmt.Errorf("got %d monomial G1 points, expected %d to match the Lagrange points", len(setupG1), width)
+	}
+	if len(setupG2) < 2 {
+		return nil, fmt.Errorf("need at least 2 G2 points, got %d", len(setupG2))
+	}
+
+	fs := kzg.NewFFTSettings(uint8(bits.Len64(width) - 1))
+	ctx := &Context{
+		fieldElementsPerBlob: width,
+		setupG1:              setupG1,
+		setupG2:              setupG2,
+	}
+	if setupLagrange == nil {
+		ks := &kzg.KZGSettings{FFTSettings: fs, SecretG1: setupG1, SecretG2: setupG2}
+		lagrange, err := ks.LagrangeG1BitReversed(width)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive Lagrange points: %w", err)
+		}
+		ctx.setupLagrange = lagrange
+	} else {
+		ctx.setupLagrange = bitReversalPermutation(setupLagrange)
+	}
+
+	// Initialize the domain: the roots of unity of the width, with the bits of the indices reversed,
+	// as specified in https://github.com/ethereum/consensus-specs/pull/3011
+	ctx.domain = bitReversalPermutationFr(fs.ExpandedRootsOfUnity[:width])
+
+	// initialize the 64 bytes of precompile return data: field elements per blob, field modulus (big-endian uint256)
+	new(big.Int).SetUint64(width).FillBytes(ctx.precompileReturnValue[:32])
+	BLSModulus.FillBytes(ctx.precompileReturnValue[32:])
+	return ctx, nil
+}
+
+// NewContextFromBytes creates a context from a trusted setup of concatenated compressed points:
+// 48 bytes per G1 point and 96 bytes per G2 point. Either the monomial or the Lagrange G1 points may be empty.
+func NewContextFromBytes(g1Monomial []byte, g1Lagrange []byte, g2Monomial []byte) (*Context, error) {
+	setupG1, err := parseG1Points(g1Monomial)
+	if err != nil {
+		return nil, fmt.Errorf("bad monomial G1 points: %w", err)
+	}
+	setupLagrange, err := parseG1Points(g1Lagrange)
+	if err != nil {
+		return nil, fmt.Errorf("bad Lagrange G1 points: %w", err)
+	}
+	setupG2, err := parseG2Points(g2Monomial)
+	if err != nil {
+		return nil, fmt.Errorf("bad G2 points: %w", err)
+	}
+	return NewContext(setupG1, setupLagrange, setupG2)
+}
+
+func parseG1Points(data []byte) ([]bls.G1Point, error) {
+	if len(data)%48 != 0 {
+		return nil, fmt.Errorf("expected a multiple of 48 bytes, got %d", len(data))
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	out := make([]bls.G1Point, len(data)/48)
+	for i := range out {
+		// a setup point is never the point at infinity, unless the secret was zero
+		p, err := bls.DecodeCompressedG1(data[i*48:(i+1)*48], false)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		bls.CopyG1(&out[i], p)
+	}
+	return out, nil
+}
+
+func parseG2Points(data []byte) ([]bls.G2Point, error) {
+	if len(data)%96 != 0 {
+		return nil, fmt.Errorf("expected a multiple of 96 bytes, got %d", len(data))
+	}
+	out := make([]bls.G2Point, len(data)/96)
+	for i := range out {
+		p, err := bls.DecodeCompressedG2(data[i*96:(i+1)*96], false)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		bls.CopyG2(&out[i], p)
+	}
+	return out, nil
+}
+
+type JSONTrustedSetup struct {
+	SetupG1       []bls.G1Point `json:"setup_G1"`
+	SetupG2       []bls.G2Point `json:"setup_G2"`
+	SetupLagrange []bls.G1Point `json:"setup_G1_lagrange"`
+}
+
+// NewContextFromJSON creates a context from a trusted setup in the JSON format of JSONTrustedSetup.
+// Either the monomial or the Lagrange G1 points may be omitted.
+func NewContextFromJSON(data []byte) (*Context, error) {
+	var parsedSetup JSONTrustedSetup
+	if err := json.Unmarshal(data, &parsedSetup); err != nil {
+		return nil, err
+	}
+	if len(parsedSetup.SetupG1) == 0 {
+		parsedSetup.SetupG1 = nil
+	}
+	if len(parsedSetup.SetupLagrange) == 0 {
+		parsedSetup.SetupLagrange = nil
+	}
+	return NewContext(parsedSetup.SetupG1, parsedSetup.SetupLagrange, parsedSetup.SetupG2)
+}
+
+// NewContextFromText creates a context from a trusted setup in the text format of the KZG ceremony (trusted_setup.txt):
+// the number of G1 points, the number of G2 points, then one hex encoded compressed point per line:
+// the G1 points in Lagrange form, the G2 points, and optionally the G1 points in monomial form.
+func NewContextFromText(data []byte) (*Context, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) < 2 {
+		return nil, errors.New("trusted setup is missing the point counts")
+	}
+	g1Count, err := strconv.ParseUint(lines[0], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("bad G1 point count: %w", err)
+	}
+	g2Count, err := strconv.ParseUint(lines[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("bad G2 point count: %w", err)
+	}
+	lines = lines[2:]
+	// Older versions of the file do not include the monomial G1 points
+	withMonomial := uint64(len(lines)) == 2*g1Count+g2Count
+	if !withMonomial && uint64(len(lines)) != g1Count+g2Count {
+		return nil, fmt.Errorf("expected %d G1 and %d G2 points, got %d lines", g1Count, g2Count, len(lines))
+	}
+	readPoints := func(lines []string, size int) ([]byte, error) {
+		out := make([]byte, 0, len(lines)*size)
+		for i, line := range lines {
+			p, err := hex.DecodeString(strings.TrimPrefix(line, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("point %d: %w", i, err)
+			}
+			if len(p) != size {
+				return nil, fmt.Errorf("point %d: expected %d bytes, got %d", i, size, len(p))
+			}
+			out = append(out, p...)
+		}
+		return out, nil
+	}
+	g1Lagrange, err := readPoints(lines[:g1Count], 48)
+	if err != nil {
+		return nil, fmt.Errorf("bad Lagrange G1 points: %w", err)
+	}
+	g2Monomial, err := readPoints(lines[g1Count:g1Count+g2Count], 96)
+	if err != nil {
+		return nil, fmt.Errorf("bad G2 points: %w", err)
+	}
+	var g1Monomial []byte
+	if withMonomial {
+		if g1Monomial, err = readPoints(lines[g1Count+g2Count:], 48); err != nil {
+			return nil, fmt.Errorf("bad monomial G1 points: %w", err)
+		}
+	}
+	return NewContextFromBytes(g1Monomial, g1Lagrange, g2Monomial)
+}
+
+// LoadContextFile creates a context from a trusted setup file.
+// Files with a .json extension are parsed with NewContextFromJSON, other files with NewContextFromText.
+func LoadContextFile(path string) (*Context, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return NewContextFromJSON(data)
+	}
+	return NewContextFromText(data)
+}
+
+// FieldElementsPerBlob returns the width of the setup of the context.
+func (ctx *Context) FieldElementsPerBlob() uint64 {
+	return ctx.fieldElementsPerBlob
+}
+
+// BytesPerBlob returns the byte length of a blob of the width of the setup of the context.
+func (ctx *Context) BytesPerBlob() uint64 {
+	return ctx.fieldElementsPerBlob * BytesPerFieldElement
+}
+
+// VerifySetup checks that the trusted setup of the context is consistent with a single secret,
+// see kzg.VerifySetup and kzg.FFTSettings.VerifyLagrangeSetup. The returned error wraps kzg.ErrInvalidSetup.
+// Setups loaded from untrusted files should be verified before use.
+func (ctx *Context) VerifySetup() error {
+	fs := kzg.NewFFTSettings(uint8(bits.Len64(ctx.fieldElementsPerBlob) - 1))
+	// the context stores the Lagrange points in bit-reversed order, the permutation is its own inverse
+	setupLagrange := bitReversalPermutation(ctx.setupLagrange)
+	setupG1 := ctx.setupG1
+	if setupG1 == nil {
+		// Without the monomial points, derive them from the Lagrange points to check the power structure.
+		for i := range setupLagrange {
+			if !bls.IsValidG1(&setupLagrange[i]) {
+				return fmt.Errorf("%w: Lagrange point %d is not in the G1 subgroup", kzg.ErrInvalidSetup, i)
+			}
+		}
+		var err error
+		if setupG1, err = fs.FFTG1(setupLagrange, false); err != nil {
+			return err
+		}
+	}
+	if err := kzg.VerifySetup(setupG1, ctx.setupG2); err != nil {
+		return err
+	}
+	return fs.VerifyLagrangeSetup(setupG1, setupLagrange)
+}
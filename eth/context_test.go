@@ -0,0 +1,211 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package eth
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+	"testing"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// testSetup generates an insecure setup of the given width, with the Lagrange points in natural order.
+func testSetup(t *testing.T, width uint64) (setupG1 []bls.G1Point, setupLagrange []bls.G1Point, setupG2 []bls.G2Point) {
+	g1, g2 := kzg.GenerateTestingSetup("1927409816240961209460912649124", width+FieldElementsPerCell+1)
+	setupG1 = g1[:width]
+	fs := kzg.NewFFTSettings(uint8(bits.Len64(width) - 1))
+	setupLagrange, err := fs.FFTG1(setupG1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return setupG1, setupLagrange, g2[:FieldElementsPerCell+1]
+}
+
+func testSetupText(setupG1 []bls.G1Point, setupLagrange []bls.G1Point, setupG2 []bls.G2Point) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n%d\n", len(setupLagrange), len(setupG2))
+	for i := range setupLagrange {
+		b.WriteString(hex.EncodeToString(bls.ToCompressedG1(&setupLagrange[i])) + "\n")
+	}
+	for i := range setupG2 {
+		b.WriteString(hex.EncodeToString(bls.ToCompressedG2(&setupG2[i])) + "\n")
+	}
+	for i := range setupG1 {
+		b.WriteString(hex.EncodeToString(bls.ToCompressedG1(&setupG1[i])) + "\n")
+	}
+	return b.String()
+}
+
+func TestContextSmallWidth(t *testing.T) {
+	const width = 128
+	setupG1, setupLagrange, setupG2 := testSetup(t, width)
+	ctx, err := NewContext(setupG1, setupLagrange, setupG2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.FieldElementsPerBlob() != width || ctx.CellsPerExtBlob() != 4 {
+		t.Fatalf("unexpected context dimensions: %d field elements, %d cells", ctx.FieldElementsPerBlob(), ctx.CellsPerExtBlob())
+	}
+	blob := make(testBlob, width)
+	for i := range blob {
+		blob[i][31] = byte(i * 3)
+		blob[i][30] = byte(i)
+	}
+	if _, ok := BlobToKZGCommitment(blob); ok {
+		t.Fatal("expected the default context to reject a blob of the wrong width")
+	}
+	commitment, ok := ctx.BlobToKZGCommitment(blob)
+	if !ok {
+		t.Fatal("invalid blob")
+	}
+
+	// the Lagrange commitment must match the commitment to the coefficients with the monomial setup
+	poly, _ := ctx.BlobToPolynomial(blob)
+	fs := kzg.NewFFTSettings(7)
+	coeffs, err := fs.FFT(bitReversalPermutationFr(poly), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected KZGCommitment
+	copy(expected[:], bls.ToCompressedG1(bls.LinCombG1(setupG1, coeffs)))
+	if commitment != expected {
+		t.Fatalf("commitment mismatch: got %x, expected %x", commitment, expected)
+	}
+
+	proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ctx.VerifyBlobKZGProof(blob, commitment, proof); err != nil || !ok {
+		t.Fatalf("blob proof did not verify: %v", err)
+	}
+	if ok, err := ctx.VerifyBlobKZGProofBatch(testBlobSequence{blob}, KZGCommitmentSequenceImpl{commitment}, KZGProofSequenceImpl{proof}); err != nil || !ok {
+		t.Fatalf("blob proof batch did not verify: %v", err)
+	}
+
+	cells, proofs, err := ctx.ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 4 || len(proofs) != 4 {
+		t.Fatalf("expected 4 cells and proofs, got %d and %d", len(cells), len(proofs))
+	}
+	commitments := []KZGCommitment{commitment, commitment, commitment, commitment}
+	ok, err = ctx.VerifyCellKZGProofBatch(commitments, []uint64{0, 1, 2, 3}, cells, proofs)
+	if err != nil || !ok {
+		t.Fatalf("cell proofs did not verify: %v", err)
+	}
+	recovered, recoveredProofs, err := ctx.RecoverCellsAndKZGProofs([]uint64{1, 2}, cells[1:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range cells {
+		if recovered[i] != cells[i] || recoveredProofs[i] != proofs[i] {
+			t.Fatalf("recovered cell %d does not match", i)
+		}
+	}
+
+	withoutMonomial, err := NewContext(nil, setupLagrange, setupG2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := withoutMonomial.ComputeCells(blob); err == nil {
+		t.Fatal("expected an error for cells without monomial setup")
+	}
+}
+
+func TestNewContextFromFormats(t *testing.T) {
+	setupG1, setupLagrange, setupG2 := testSetup(t, 16)
+	ctx, err := NewContext(setupG1, setupLagrange, setupG2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := make(testBlob, 16)
+	for i := range blob {
+		blob[i][31] = byte(i + 1)
+	}
+	expected, _ := ctx.BlobToKZGCommitment(blob)
+
+	jsonData, err := json.Marshal(&JSONTrustedSetup{SetupG1: setupG1, SetupG2: setupG2, SetupLagrange: setupLagrange})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g1Bytes, lagrangeBytes, g2Bytes []byte
+	for i := range setupG1 {
+		g1Bytes = append(g1Bytes, bls.ToCompressedG1(&setupG1[i])...)
+		lagrangeBytes = append(lagrangeBytes, bls.ToCompressedG1(&setupLagrange[i])...)
+	}
+	for i := range setupG2 {
+		g2Bytes = append(g2Bytes, bls.ToCompressedG2(&setupG2[i])...)
+	}
+	loaders := map[string]func() (*Context, error){
+		"json": func() (*Context, error) { return NewContextFromJSON(jsonData) },
+		"text": func() (*Context, error) {
+			return NewContextFromText([]byte(testSetupText(setupG1, setupLagrange, setupG2)))
+		},
+		"text_without_monomial": func() (*Context, error) {
+			return NewContextFromText([]byte(testSetupText(nil, setupLagrange, setupG2)))
+		},
+		"bytes": func() (*Context, error) { return NewContextFromBytes(g1Bytes, lagrangeBytes, g2Bytes) },
+		"bytes_without_lagrange": func() (*Context, error) {
+			return NewContextFromBytes(g1Bytes, nil, g2Bytes)
+		},
+		"derived_lagrange": func() (*Context, error) { return NewContext(setupG1, nil, setupG2) },
+	}
+	for name, load := range loaders {
+		t.Run(name, func(t *testing.T) {
+			loaded, err := load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := loaded.BlobToKZGCommitment(blob); got != expected {
+				t.Fatalf("commitment mismatch: got %x, expected %x", got, expected)
+			}
+		})
+	}
+
+	if _, err := ctx.ComputeCells(blob); err == nil {
+		t.Fatal("expected an error for cells of a blob smaller than two cells")
+	}
+	if _, err := NewContext(nil, setupLagrange[:15], setupG2); err == nil {
+		t.Fatal("expected an error for a width that is not a power of two")
+	}
+	if _, err := NewContextFromText([]byte(testSetupText(nil, setupLagrange, setupG2[:1]))); err == nil {
+		t.Fatal("expected an error for a setup without [s]_2")
+	}
+	if _, err := NewContextFromText([]byte("16\n65\n")); err == nil {
+		t.Fatal("expected an error for a truncated setup")
+	}
+}
+
+func TestContextVerifySetup(t *testing.T) {
+	if err := DefaultContext().VerifySetup(); err != nil {
+		t.Fatalf("mainnet setup did not verify: %v", err)
+	}
+	setupG1, setupLagrange, setupG2 := testSetup(t, 16)
+	withoutMonomial, err := NewContext(nil, setupLagrange, setupG2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := withoutMonomial.VerifySetup(); err != nil {
+		t.Fatal(err)
+	}
+	bad := append([]bls.G1Point{}, setupLagrange...)
+	bad[2], bad[3] = bad[3], bad[2]
+	for name, ctxSetup := range map[string][]bls.G1Point{"with_monomial": setupG1, "without_monomial": nil} {
+		ctx, err := NewContext(ctxSetup, bad, setupG2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.VerifySetup(); !errors.Is(err, kzg.ErrInvalidSetup) {
+			t.Fatalf("%s: expected invalid setup error for swapped Lagrange points, got: %v", name, err)
+		}
+	}
+}
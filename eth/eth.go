@@ -2,10 +2,10 @@
 // +build !bignum_pure,!bignum_hol256
 
 // Package eth implements the various EIP-4844 function specifications as defined
-// in the EIP-4844 proposal and the EIP-4844 consensus specs:
+// in the EIP-4844 proposal and the Deneb consensus specs:
 //
 //	https://eips.ethereum.org/EIPS/eip-4844
-//	https://github.com/roberto-bayardo/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md
+//	https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
 package eth
 
 import (
@@ -14,28 +14,24 @@ import (
 	"errors"
 	"fmt"
 
+	kzg "github.com/protolambda/go-kzg"
 	"github.com/protolambda/go-kzg/bls"
 )
 
 const (
 	BlobCommitmentVersionKZG uint8 = 0x01
 	FieldElementsPerBlob           = 4096
+	BytesPerFieldElement           = 32
+	BytesPerBlob                   = FieldElementsPerBlob * BytesPerFieldElement
+	BytesPerCommitment             = 48
+	BytesPerProof                  = 48
 )
 
-// The custom types from EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#custom-types
-type KZGCommitment [48]byte
-type KZGProof [48]byte
+// The custom types from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#custom-types
+type KZGCommitment [BytesPerCommitment]byte
+type KZGProof [BytesPerProof]byte
 type VersionedHash [32]byte
-type Root [32]byte
-type Slot uint64
-
-type BlobsSidecar struct {
-	BeaconBlockRoot    Root
-	BeaconBlockSlot    Slot
-	Blobs              BlobSequence
-	KZGAggregatedProof KZGProof
-}
 
 type BlobSequence interface {
 	Len() int
@@ -62,6 +58,21 @@ func (s KZGCommitmentSequenceImpl) Len() int {
 	return len(s)
 }
 
+type KZGProofSequence interface {
+	Len() int
+	At(int) KZGProof
+}
+
+type KZGProofSequenceImpl []KZGProof
+
+func (s KZGProofSequenceImpl) At(i int) KZGProof {
+	return s[i]
+}
+
+func (s KZGProofSequenceImpl) Len() int {
+	return len(s)
+}
+
 const (
 	BlobTxType                = 5
 	PrecompileInputLength     = 192
@@ -70,10 +81,11 @@ const (
 
 var (
 	errInvalidKZGProof = errors.New("invalid kzg proof")
+	errInvalidBlob     = errors.New("invalid blob")
 )
 
 // PointEvaluationPrecompile implements point_evaluation_precompile from EIP-4844
-func PointEvaluationPrecompile(input []byte) ([]byte, error) {
+func (ctx *Context) PointEvaluationPrecompile(input []byte) ([]byte, error) {
 	if len(input) != PrecompileInputLength {
 		return nil, errors.New("invalid input length")
 	}
@@ -98,20 +110,22 @@ func PointEvaluationPrecompile(input []byte) ([]byte, error) {
 	var quotientKZG [48]byte
 	copy(quotientKZG[:], input[144:PrecompileInputLength])
 
-	ok, err := VerifyKZGProof(KZGCommitment(dataKZG), x, y, KZGProof(quotientKZG))
+	ok, err := ctx.VerifyKZGProof(KZGCommitment(dataKZG), x, y, KZGProof(quotientKZG))
 	if err != nil {
-		return nil, fmt.Errorf("verify_kzg_proof error: %v", err)
+		return nil, fmt.Errorf("verify_kzg_proof error: %w", err)
 	}
 	if !ok {
 		return nil, errInvalidKZGProof
 	}
-	result := precompileReturnValue // copy the value
+	result := ctx.precompileReturnValue // copy the value
 	return result[:], nil
 }
 
-// VerifyKZGProof implements verify_kzg_proof from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#verify_kzg_proof
-func VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
+// VerifyKZGProof implements verify_kzg_proof from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#verify_kzg_proof
+//
+// The evaluation point z and the claimed evaluation y are big-endian encoded field elements.
+func (ctx *Context) VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
 	// successfully converting z and y to bls.Fr confirms they are < MODULUS per the spec
 	var zFr, yFr bls.Fr
 	ok := bytesToBLSField(&zFr, z)
@@ -122,15 +136,68 @@ func VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProo
 	if !ok {
 		return false, errors.New("invalid expected output")
 	}
-	polynomialKZGG1, err := bls.FromCompressedG1(polynomialKZG[:])
+	polynomialKZGG1, err := bytesToKZGCommitment(polynomialKZG)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode polynomialKZG: %v", err)
+		return false, fmt.Errorf("failed to decode polynomialKZG: %w", err)
 	}
-	kzgProofG1, err := bls.FromCompressedG1(kzgProof[:])
+	kzgProofG1, err := bytesToKZGProof(kzgProof)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode kzgProof: %v", err)
+		return false, fmt.Errorf("failed to decode kzgProof: %w", err)
+	}
+	return ctx.VerifyKZGProofFromPoints(polynomialKZGG1, &zFr, &yFr, kzgProofG1), nil
+}
+
+// VerifyKZGProofBatch verifies a batch of verify_kzg_proof claims with a single pairing check.
+// Like VerifyKZGProof, an error is only returned for malformed input, an invalid proof returns false.
+// If the batch does not verify, use InvalidKZGProofs to find which proofs are invalid.
+func (ctx *Context) VerifyKZGProofBatch(polynomialKZGs []KZGCommitment, zs, ys [][32]byte, kzgProofs []KZGProof) (bool, error) {
+	commitmentsG1, zsFr, ysFr, proofsG1, err := parseKZGProofBatch(polynomialKZGs, zs, ys, kzgProofs)
+	if err != nil {
+		return false, err
+	}
+	return ctx.VerifyKZGProofBatchFromPoints(commitmentsG1, zsFr, ysFr, proofsG1)
+}
+
+// InvalidKZGProofs returns the indices of the verify_kzg_proof claims of the batch that do not verify, in ascending order.
+// The batch is recursively split in halves (see kzg.InvalidProofsSingle), so a single invalid proof
+// costs a logarithmic number of pairing checks, instead of one per proof.
+func (ctx *Context) InvalidKZGProofs(polynomialKZGs []KZGCommitment, zs, ys [][32]byte, kzgProofs []KZGProof) ([]int, error) {
+	commitmentsG1, zsFr, ysFr, proofsG1, err := parseKZGProofBatch(polynomialKZGs, zs, ys, kzgProofs)
+	if err != nil {
+		return nil, err
+	}
+	return kzg.InvalidProofsSingleWithSetup(&ctx.setupG2[1], commitmentsG1, proofsG1, zsFr, ysFr)
+}
+
+func parseKZGProofBatch(polynomialKZGs []KZGCommitment, zs, ys [][32]byte, kzgProofs []KZGProof) (commitmentsG1 []bls.G1Point, zsFr []bls.Fr, ysFr []bls.Fr, proofsG1 []bls.G1Point, err error) {
+	n := len(polynomialKZGs)
+	if len(zs) != n || len(ys) != n || len(kzgProofs) != n {
+		return nil, nil, nil, nil, fmt.Errorf("%w: %d commitments, %d zs, %d ys, %d proofs",
+			kzg.ErrLengthMismatch, n, len(zs), len(ys), len(kzgProofs))
+	}
+	commitmentsG1 = make([]bls.G1Point, n)
+	proofsG1 = make([]bls.G1Point, n)
+	zsFr = make([]bls.Fr, n)
+	ysFr = make([]bls.Fr, n)
+	for i := 0; i < n; i++ {
+		if !bytesToBLSField(&zsFr[i], zs[i]) {
+			return nil, nil, nil, nil, fmt.Errorf("invalid evaluation point %d", i)
+		}
+		if !bytesToBLSField(&ysFr[i], ys[i]) {
+			return nil, nil, nil, nil, fmt.Errorf("invalid expected output %d", i)
+		}
+		p, err := bytesToKZGCommitment(polynomialKZGs[i])
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to decode polynomialKZG %d: %w", i, err)
+		}
+		bls.CopyG1(&commitmentsG1[i], p)
+		p, err = bytesToKZGProof(kzgProofs[i])
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to decode kzgProof %d: %w", i, err)
+		}
+		bls.CopyG1(&proofsG1[i], p)
 	}
-	return VerifyKZGProofFromPoints(polynomialKZGG1, &zFr, &yFr, kzgProofG1), nil
+	return commitmentsG1, zsFr, ysFr, proofsG1, nil
 }
 
 // KZGToVersionedHash implements kzg_to_versioned_hash from EIP-4844
@@ -140,71 +207,104 @@ func KZGToVersionedHash(kzg KZGCommitment) VersionedHash {
 	return VersionedHash(h)
 }
 
-// BlobToKZGCommitment implements blob_to_kzg_commitment from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#blob_to_kzg_commitment
-func BlobToKZGCommitment(blob Blob) (KZGCommitment, bool) {
-	poly, ok := BlobToPolynomial(blob)
+// BlobToKZGCommitment implements blob_to_kzg_commitment from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#blob_to_kzg_commitment
+func (ctx *Context) BlobToKZGCommitment(blob Blob) (KZGCommitment, bool) {
+	poly, ok := ctx.BlobToPolynomial(blob)
 	if !ok {
 		return KZGCommitment{}, false
 	}
-	return PolynomialToKZGCommitment(poly), true
+	return ctx.PolynomialToKZGCommitment(poly), true
 }
 
-// VerifyAggregateKZGProof implements verify_aggregate_kzg_proof from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#verify_aggregate_kzg_proof
-func VerifyAggregateKZGProof(blobs BlobSequence, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
-	polynomials, ok := BlobsToPolynomials(blobs)
+// ComputeKZGProof implements compute_kzg_proof from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_kzg_proof
+//
+// It returns the proof for the evaluation of the blob polynomial at z, and the big-endian encoded evaluation y.
+// The evaluation point z may be within the domain of the blob.
+func (ctx *Context) ComputeKZGProof(blob Blob, z [32]byte) (KZGProof, [32]byte, error) {
+	poly, ok := ctx.BlobToPolynomial(blob)
 	if !ok {
-		return false, errors.New("could not convert blobs to polynomials")
+		return KZGProof{}, [32]byte{}, errInvalidBlob
 	}
-	aggregatedPoly, aggregatedPolyCommitment, evaluationChallenge, err :=
-		ComputeAggregatedPolyAndCommitment(polynomials, expectedKZGCommitments)
-	if err != nil {
-		return false, err
+	var zFr bls.Fr
+	if !bytesToBLSField(&zFr, z) {
+		return KZGProof{}, [32]byte{}, errors.New("invalid evaluation point")
 	}
-	y := EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge)
-	kzgProofG1, err := bls.FromCompressedG1(kzgAggregatedProof[:])
+	proof, y, err := ctx.ComputeKZGProofFromPolynomial(poly, &zFr)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode kzgProof: %v", err)
+		return KZGProof{}, [32]byte{}, err
 	}
-	return VerifyKZGProofFromPoints(aggregatedPolyCommitment, evaluationChallenge, y, kzgProofG1), nil
+	return proof, blsFieldToBytes(y), nil
 }
 
-// ComputeAggregateKZGProof implements compute_aggregate_kzg_proof from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_aggregate_kzg_proof
-func ComputeAggregateKZGProof(blobs BlobSequence) (KZGProof, error) {
-	polynomials, ok := BlobsToPolynomials(blobs)
+// ComputeBlobKZGProof implements compute_blob_kzg_proof from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_blob_kzg_proof
+//
+// The commitment is not verified to match the blob, the caller is expected to have computed it with BlobToKZGCommitment.
+func (ctx *Context) ComputeBlobKZGProof(blob Blob, commitment KZGCommitment) (KZGProof, error) {
+	poly, ok := ctx.BlobToPolynomial(blob)
 	if !ok {
-		return KZGProof{}, errors.New("could not convert blobs to polynomials")
+		return KZGProof{}, errInvalidBlob
+	}
+	if _, err := bytesToKZGCommitment(commitment); err != nil {
+		return KZGProof{}, fmt.Errorf("failed to decode commitment: %v", err)
 	}
-	return ComputeAggregateKZGProofFromPolynomials(polynomials)
+	evaluationChallenge := ctx.computeChallenge(blob, commitment)
+	proof, _, err := ctx.ComputeKZGProofFromPolynomial(poly, evaluationChallenge)
+	return proof, err
 }
 
-// ValidateBlobsSidecar implements validate_blobs_sidecar from the EIP-4844 consensus spec:
-// https://github.com/roberto-bayardo/consensus-specs/blob/dev/specs/eip4844/beacon-chain.md#validate_blobs_sidecar
-func ValidateBlobsSidecar(slot Slot, beaconBlockRoot Root, expectedKZGCommitments KZGCommitmentSequence, blobsSidecar BlobsSidecar) error {
-	if slot != blobsSidecar.BeaconBlockSlot {
-		return fmt.Errorf(
-			"slot doesn't match sidecar's beacon block slot (%v != %v)",
-			slot, blobsSidecar.BeaconBlockSlot)
-	}
-	if beaconBlockRoot != blobsSidecar.BeaconBlockRoot {
-		return errors.New("roots not equal")
-	}
-	blobs := blobsSidecar.Blobs
-	if blobs.Len() != expectedKZGCommitments.Len() {
-		return fmt.Errorf(
-			"blob len doesn't match expected kzg commitments len (%v != %v)",
-			blobs.Len(), expectedKZGCommitments.Len())
+// VerifyBlobKZGProof implements verify_blob_kzg_proof from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof
+func (ctx *Context) VerifyBlobKZGProof(blob Blob, commitment KZGCommitment, proof KZGProof) (bool, error) {
+	poly, ok := ctx.BlobToPolynomial(blob)
+	if !ok {
+		return false, errInvalidBlob
 	}
-	ok, err := VerifyAggregateKZGProof(blobs, expectedKZGCommitments, blobsSidecar.KZGAggregatedProof)
+	commitmentG1, proofG1, err := parseBlobCommitmentAndProof(commitment, proof)
 	if err != nil {
-		return fmt.Errorf("verify_aggregate_kzg_proof error: %v", err)
+		return false, err
 	}
-	if !ok {
-		return errInvalidKZGProof
+	evaluationChallenge := ctx.computeChallenge(blob, commitment)
+	y := ctx.EvaluatePolynomialInEvaluationForm(poly, evaluationChallenge)
+	return ctx.VerifyKZGProofFromPoints(commitmentG1, evaluationChallenge, y, proofG1), nil
+}
+
+// VerifyBlobKZGProofBatch implements verify_blob_kzg_proof_batch from the Deneb consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
+//
+// The claims are combined with powers of a challenge derived from all inputs into a single pairing check.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	n := blobs.Len()
+	if commitments.Len() != n || proofs.Len() != n {
+		return false, fmt.Errorf("batch input lengths don't match: %d blobs, %d commitments, %d proofs",
+			n, commitments.Len(), proofs.Len())
 	}
-	return nil
+	commitmentsBytes := make([]KZGCommitment, n)
+	commitmentsG1 := make([]bls.G1Point, n)
+	proofsBytes := make([]KZGProof, n)
+	proofsG1 := make([]bls.G1Point, n)
+	zs := make([]bls.Fr, n)
+	ys := make([]bls.Fr, n)
+	for i := 0; i < n; i++ {
+		blob := blobs.At(i)
+		poly, ok := ctx.BlobToPolynomial(blob)
+		if !ok {
+			return false, fmt.Errorf("%w: blob %d", errInvalidBlob, i)
+		}
+		commitmentsBytes[i] = commitments.At(i)
+		proofsBytes[i] = proofs.At(i)
+		commitmentG1, proofG1, err := parseBlobCommitmentAndProof(commitmentsBytes[i], proofsBytes[i])
+		if err != nil {
+			return false, fmt.Errorf("blob %d: %w", i, err)
+		}
+		bls.CopyG1(&commitmentsG1[i], commitmentG1)
+		bls.CopyG1(&proofsG1[i], proofG1)
+		bls.CopyFr(&zs[i], ctx.computeChallenge(blob, commitmentsBytes[i]))
+		bls.CopyFr(&ys[i], ctx.EvaluatePolynomialInEvaluationForm(poly, &zs[i]))
+	}
+	return ctx.verifyKZGProofBatch(commitmentsBytes, commitmentsG1, zs, ys, proofsBytes, proofsG1)
 }
 
 // TxPeekBlobVersionedHashes implements tx_peek_blob_versioned_hashes from EIP-4844 consensus spec:
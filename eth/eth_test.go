@@ -0,0 +1,567 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+	"gopkg.in/yaml.v3"
+)
+
+// The spec test vectors in testdata are a subset of the official consensus-spec KZG test vectors,
+// in the same layout: testdata/<function>/<case>/data.yaml
+// Set KZG_SPEC_TESTS_DIR to the "tests" directory of the full official test suite to run all of them instead.
+func specTestCases(t *testing.T, function string) []string {
+	dir := filepath.Join("testdata", function)
+	if full := os.Getenv("KZG_SPEC_TESTS_DIR"); full != "" {
+		dir = filepath.Join(full, function, "kzg-mainnet")
+	}
+	cases, err := filepath.Glob(filepath.Join(dir, "*", "data.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no test cases found for %s in %s", function, dir)
+	}
+	return cases
+}
+
+func runSpecTests(t *testing.T, function string, fn func(t *testing.T, data []byte)) {
+	for _, path := range specTestCases(t, function) {
+		name := filepath.Base(filepath.Dir(path))
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fn(t, data)
+		})
+	}
+}
+
+type testBlob [][32]byte
+
+func (b testBlob) Len() int {
+	return len(b)
+}
+
+func (b testBlob) At(i int) [32]byte {
+	return b[i]
+}
+
+type testBlobSequence []testBlob
+
+func (s testBlobSequence) Len() int {
+	return len(s)
+}
+
+func (s testBlobSequence) At(i int) Blob {
+	return s[i]
+}
+
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("missing 0x prefix: %q", s)
+	}
+	return hex.DecodeString(s[2:])
+}
+
+func decodeBytes32(s string) ([32]byte, error) {
+	var out [32]byte
+	b, err := decodeHex(s)
+	if err != nil {
+		return out, err
+	}
+	if len(b) != 32 {
+		return out, fmt.Errorf("expected 32 bytes, got %d", len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
+func decodeBytes48(s string) ([48]byte, error) {
+	var out [48]byte
+	b, err := decodeHex(s)
+	if err != nil {
+		return out, err
+	}
+	if len(b) != 48 {
+		return out, fmt.Errorf("expected 48 bytes, got %d", len(b))
+	}
+	copy(out[:], b)
+	return out, nil
+}
+
+func decodeBlob(s string) (testBlob, error) {
+	b, err := decodeHex(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != BytesPerBlob {
+		return nil, fmt.Errorf("expected %d bytes, got %d", BytesPerBlob, len(b))
+	}
+	out := make(testBlob, FieldElementsPerBlob)
+	for i := range out {
+		copy(out[i][:], b[i*BytesPerFieldElement:(i+1)*BytesPerFieldElement])
+	}
+	return out, nil
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	runSpecTests(t, "blob_to_kzg_commitment", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob string `yaml:"blob"`
+			} `yaml:"input"`
+			Output *string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := decodeBlob(test.Input.Blob)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		commitment, ok := BlobToKZGCommitment(blob)
+		if test.Output == nil {
+			if ok {
+				t.Fatal("expected invalid blob")
+			}
+			return
+		}
+		if !ok {
+			t.Fatal("unexpected invalid blob")
+		}
+		expected, err := decodeBytes48(*test.Output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commitment != expected {
+			t.Fatalf("commitment mismatch: got %x, expected %x", commitment, expected)
+		}
+	})
+}
+
+func TestComputeKZGProof(t *testing.T) {
+	runSpecTests(t, "compute_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob string `yaml:"blob"`
+				Z    string `yaml:"z"`
+			} `yaml:"input"`
+			Output *[]string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := decodeBlob(test.Input.Blob)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		z, err := decodeBytes32(test.Input.Z)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		proof, y, err := ComputeKZGProof(blob, z)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedProof, err := decodeBytes48((*test.Output)[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedY, err := decodeBytes32((*test.Output)[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expectedProof {
+			t.Fatalf("proof mismatch: got %x, expected %x", proof, expectedProof)
+		}
+		if y != expectedY {
+			t.Fatalf("y mismatch: got %x, expected %x", y, expectedY)
+		}
+	})
+}
+
+func TestComputeBlobKZGProof(t *testing.T) {
+	runSpecTests(t, "compute_blob_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob       string `yaml:"blob"`
+				Commitment string `yaml:"commitment"`
+			} `yaml:"input"`
+			Output *string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := decodeBlob(test.Input.Blob)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		commitment, err := decodeBytes48(test.Input.Commitment)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		proof, err := ComputeBlobKZGProof(blob, commitment)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := decodeBytes48(*test.Output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expected {
+			t.Fatalf("proof mismatch: got %x, expected %x", proof, expected)
+		}
+	})
+}
+
+func TestVerifyKZGProof(t *testing.T) {
+	runSpecTests(t, "verify_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Commitment string `yaml:"commitment"`
+				Z          string `yaml:"z"`
+				Y          string `yaml:"y"`
+				Proof      string `yaml:"proof"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		commitment, err1 := decodeBytes48(test.Input.Commitment)
+		z, err2 := decodeBytes32(test.Input.Z)
+		y, err3 := decodeBytes32(test.Input.Y)
+		proof, err4 := decodeBytes48(test.Input.Proof)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			if test.Output != nil {
+				t.Fatal("failed to decode valid input")
+			}
+			return
+		}
+		ok, err := VerifyKZGProof(commitment, z, y, proof)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != *test.Output {
+			t.Fatalf("got %v, expected %v", ok, *test.Output)
+		}
+		// the precompile wraps the same check
+		input := make([]byte, 0, PrecompileInputLength)
+		versionedHash := KZGToVersionedHash(commitment)
+		input = append(input, versionedHash[:]...)
+		input = append(input, z[:]...)
+		input = append(input, y[:]...)
+		input = append(input, commitment[:]...)
+		input = append(input, proof[:]...)
+		out, err := PointEvaluationPrecompile(input)
+		if ok != (err == nil) {
+			t.Fatalf("precompile result %v does not match verification result %v", err, ok)
+		}
+		if ok && string(out) != string(DefaultContext().precompileReturnValue[:]) {
+			t.Fatalf("unexpected precompile output: %x", out)
+		}
+	})
+}
+
+func TestVerifyBlobKZGProof(t *testing.T) {
+	runSpecTests(t, "verify_blob_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob       string `yaml:"blob"`
+				Commitment string `yaml:"commitment"`
+				Proof      string `yaml:"proof"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err1 := decodeBlob(test.Input.Blob)
+		commitment, err2 := decodeBytes48(test.Input.Commitment)
+		proof, err3 := decodeBytes48(test.Input.Proof)
+		if err1 != nil || err2 != nil || err3 != nil {
+			if test.Output != nil {
+				t.Fatal("failed to decode valid input")
+			}
+			return
+		}
+		ok, err := VerifyBlobKZGProof(blob, commitment, proof)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != *test.Output {
+			t.Fatalf("got %v, expected %v", ok, *test.Output)
+		}
+	})
+}
+
+func TestVerifyBlobKZGProofBatch(t *testing.T) {
+	runSpecTests(t, "verify_blob_kzg_proof_batch", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blobs       []string `yaml:"blobs"`
+				Commitments []string `yaml:"commitments"`
+				Proofs      []string `yaml:"proofs"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		var blobs testBlobSequence
+		var commitments KZGCommitmentSequenceImpl
+		var proofs KZGProofSequenceImpl
+		for _, s := range test.Input.Blobs {
+			blob, err := decodeBlob(s)
+			if err != nil {
+				if test.Output != nil {
+					t.Fatalf("failed to decode valid input: %v", err)
+				}
+				return
+			}
+			blobs = append(blobs, blob)
+		}
+		for _, s := range test.Input.Commitments {
+			commitment, err := decodeBytes48(s)
+			if err != nil {
+				if test.Output != nil {
+					t.Fatalf("failed to decode valid input: %v", err)
+				}
+				return
+			}
+			commitments = append(commitments, commitment)
+		}
+		for _, s := range test.Input.Proofs {
+			proof, err := decodeBytes48(s)
+			if err != nil {
+				if test.Output != nil {
+					t.Fatalf("failed to decode valid input: %v", err)
+				}
+				return
+			}
+			proofs = append(proofs, proof)
+		}
+		ok, err := VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != *test.Output {
+			t.Fatalf("got %v, expected %v", ok, *test.Output)
+		}
+	})
+}
+
+func TestComputeChallenge(t *testing.T) {
+	runSpecTests(t, "compute_challenge", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob       string `yaml:"blob"`
+				Commitment string `yaml:"commitment"`
+			} `yaml:"input"`
+			Output string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := decodeBlob(test.Input.Blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitment, err := decodeBytes48(test.Input.Commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := decodeBytes32(test.Output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		challenge := DefaultContext().computeChallenge(blob, commitment)
+		if got := blsFieldToBytes(challenge); got != expected {
+			t.Fatalf("challenge mismatch: got %x, expected %x", got, expected)
+		}
+	})
+}
+
+func TestComputeKZGProofRoundTrip(t *testing.T) {
+	blob := make(testBlob, FieldElementsPerBlob)
+	for i := range blob {
+		blob[i][31] = byte(i)
+		blob[i][30] = byte(i >> 8)
+	}
+	commitment, ok := BlobToKZGCommitment(blob)
+	if !ok {
+		t.Fatal("invalid blob")
+	}
+	// one point outside the domain, and one point within the domain
+	inDomain := blsFieldToBytes(&DefaultContext().domain[42])
+	for _, z := range [][32]byte{{31: 123}, inDomain} {
+		proof, y, err := ComputeKZGProof(blob, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := VerifyKZGProof(commitment, z, y, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("proof for z=%x did not verify", z)
+		}
+	}
+	if y := blsFieldToBytes(EvaluatePolynomialInEvaluationForm(mustPoly(t, blob), &DefaultContext().domain[42])); y != blob[42] {
+		t.Fatalf("evaluation within the domain mismatch: got %x, expected %x", y, blob[42])
+	}
+}
+
+func mustPoly(t *testing.T, blob Blob) Polynomial {
+	poly, ok := BlobToPolynomial(blob)
+	if !ok {
+		t.Fatal("invalid blob")
+	}
+	return poly
+}
+
+func TestVerifyKZGProofStrictDecoding(t *testing.T) {
+	// the zero polynomial: both the commitment and the proof are the point at infinity, which is accepted
+	var infinity [48]byte
+	infinity[0] = 0xc0
+	z := [32]byte{31: 123}
+	ok, err := VerifyKZGProof(KZGCommitment(infinity), z, [32]byte{}, KZGProof(infinity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("proof of the zero polynomial did not verify")
+	}
+
+	nonCanonicalInfinity := infinity
+	nonCanonicalInfinity[47] = 1
+	uncompressedFlag := infinity
+	uncompressedFlag[0] = 0x40
+	for _, enc := range [][48]byte{nonCanonicalInfinity, uncompressedFlag} {
+		if _, err := VerifyKZGProof(KZGCommitment(infinity), z, [32]byte{}, KZGProof(enc)); !errors.Is(err, bls.ErrInvalidEncoding) {
+			t.Fatalf("expected invalid proof encoding %x to be rejected, got %v", enc, err)
+		}
+		input := make([]byte, 0, PrecompileInputLength)
+		versionedHash := KZGToVersionedHash(KZGCommitment(enc))
+		input = append(input, versionedHash[:]...)
+		input = append(input, z[:]...)
+		input = append(input, make([]byte, 32)...)
+		input = append(input, enc[:]...)
+		input = append(input, infinity[:]...)
+		if _, err := PointEvaluationPrecompile(input); !errors.Is(err, bls.ErrInvalidEncoding) {
+			t.Fatalf("expected invalid commitment encoding %x to be rejected by the precompile, got %v", enc, err)
+		}
+	}
+}
+
+func TestVerifyKZGProofBatch(t *testing.T) {
+	blob := make(testBlob, FieldElementsPerBlob)
+	for i := range blob {
+		blob[i][31] = byte(i)
+		blob[i][30] = byte(i >> 8)
+	}
+	commitment, ok := BlobToKZGCommitment(blob)
+	if !ok {
+		t.Fatal("invalid blob")
+	}
+	const n = 5
+	commitments := make([]KZGCommitment, n)
+	zs := make([][32]byte, n)
+	ys := make([][32]byte, n)
+	proofs := make([]KZGProof, n)
+	for i := 0; i < n; i++ {
+		zs[i] = [32]byte{31: byte(100 + i)}
+		proof, y, err := ComputeKZGProof(blob, zs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitments[i], ys[i], proofs[i] = commitment, y, proof
+	}
+	ok, err := VerifyKZGProofBatch(commitments, zs, ys, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("valid batch did not verify")
+	}
+	if invalid, err := InvalidKZGProofs(commitments, zs, ys, proofs); err != nil || len(invalid) != 0 {
+		t.Fatalf("expected no invalid proofs, got %v, %v", invalid, err)
+	}
+
+	// a valid proof, for a different claim: an invalid proof is not an error
+	proofs[3] = proofs[2]
+	ok, err = VerifyKZGProofBatch(commitments, zs, ys, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("batch with an invalid proof verified")
+	}
+	if invalid, err := InvalidKZGProofs(commitments, zs, ys, proofs); err != nil || len(invalid) != 1 || invalid[0] != 3 {
+		t.Fatalf("expected proof 3 to be invalid, got %v, %v", invalid, err)
+	}
+
+	if _, err := VerifyKZGProofBatch(commitments, zs, ys, proofs[:n-1]); !errors.Is(err, kzg.ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch error, got %v", err)
+	}
+	if _, err := VerifyKZGProofBatchFromPoints(make([]bls.G1Point, 2), make([]bls.Fr, 1), make([]bls.Fr, 2), make([]bls.G1Point, 2)); !errors.Is(err, kzg.ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch error, got %v", err)
+	}
+}
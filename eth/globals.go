@@ -5,68 +5,137 @@ package eth
 
 import (
 	_ "embed"
-	"encoding/json"
 	"math/big"
+	"sync"
 
 	"github.com/protolambda/go-kzg/bls"
 )
 
 var (
 	BLSModulus *big.Int
-	DomainFr   []bls.Fr
-
-	// KZG CRS for G2
-	kzgSetupG2 []bls.G2Point
-
-	// KZG CRS for commitment computation
-	kzgSetupLagrange []bls.G1Point
-
-	// KZG CRS for G1 (only used in tests (for proof creation))
-	KzgSetupG1 []bls.G1Point
 
+	// The mainnet trusted setup of the KZG ceremony, used by the DefaultContext
 	//go:embed trusted_setup.json
 	kzgSetupStr string
 
-	precompileReturnValue [64]byte
+	defaultContextOnce sync.Once
+	defaultContext     *Context
 )
 
-type JSONTrustedSetup struct {
-	SetupG1       []bls.G1Point `json:"setup_G1"`
-	SetupG2       []bls.G2Point `json:"setup_G2"`
-	SetupLagrange []bls.G1Point `json:"setup_G1_lagrange"`
+func init() {
+	BLSModulus = new(big.Int)
+	BLSModulus.SetString(bls.ModulusStr, 10)
 }
 
-func init() {
-	// Initialize KZG subsystem (load the trusted setup data)
-	var parsedSetup = JSONTrustedSetup{}
+// DefaultContext returns the context of the embedded mainnet trusted setup,
+// it is loaded on first use. The package-level functions all use this context.
+func DefaultContext() *Context {
+	defaultContextOnce.Do(func() {
+		ctx, err := NewContextFromJSON([]byte(kzgSetupStr))
+		if err != nil {
+			panic(err)
+		}
+		defaultContext = ctx
+	})
+	return defaultContext
+}
 
-	err := json.Unmarshal([]byte(kzgSetupStr), &parsedSetup)
-	if err != nil {
-		panic(err)
-	}
-	kzgSetupG2 = parsedSetup.SetupG2
-	kzgSetupLagrange = bitReversalPermutation(parsedSetup.SetupLagrange)
-	KzgSetupG1 = parsedSetup.SetupG1
+// PointEvaluationPrecompile runs Context.PointEvaluationPrecompile with the DefaultContext.
+func PointEvaluationPrecompile(input []byte) ([]byte, error) {
+	return DefaultContext().PointEvaluationPrecompile(input)
+}
 
-	BLSModulus = new(big.Int)
-	BLSModulus.SetString(bls.ModulusStr, 10)
+// VerifyKZGProof runs Context.VerifyKZGProof with the DefaultContext.
+func VerifyKZGProof(polynomialKZG KZGCommitment, z, y [32]byte, kzgProof KZGProof) (bool, error) {
+	return DefaultContext().VerifyKZGProof(polynomialKZG, z, y, kzgProof)
+}
+
+// VerifyKZGProofBatch runs Context.VerifyKZGProofBatch with the DefaultContext.
+func VerifyKZGProofBatch(polynomialKZGs []KZGCommitment, zs, ys [][32]byte, kzgProofs []KZGProof) (bool, error) {
+	return DefaultContext().VerifyKZGProofBatch(polynomialKZGs, zs, ys, kzgProofs)
+}
+
+// InvalidKZGProofs runs Context.InvalidKZGProofs with the DefaultContext.
+func InvalidKZGProofs(polynomialKZGs []KZGCommitment, zs, ys [][32]byte, kzgProofs []KZGProof) ([]int, error) {
+	return DefaultContext().InvalidKZGProofs(polynomialKZGs, zs, ys, kzgProofs)
+}
+
+// BlobToKZGCommitment runs Context.BlobToKZGCommitment with the DefaultContext.
+func BlobToKZGCommitment(blob Blob) (KZGCommitment, bool) {
+	return DefaultContext().BlobToKZGCommitment(blob)
+}
+
+// ComputeKZGProof runs Context.ComputeKZGProof with the DefaultContext.
+func ComputeKZGProof(blob Blob, z [32]byte) (KZGProof, [32]byte, error) {
+	return DefaultContext().ComputeKZGProof(blob, z)
+}
+
+// ComputeBlobKZGProof runs Context.ComputeBlobKZGProof with the DefaultContext.
+func ComputeBlobKZGProof(blob Blob, commitment KZGCommitment) (KZGProof, error) {
+	return DefaultContext().ComputeBlobKZGProof(blob, commitment)
+}
+
+// VerifyBlobKZGProof runs Context.VerifyBlobKZGProof with the DefaultContext.
+func VerifyBlobKZGProof(blob Blob, commitment KZGCommitment, proof KZGProof) (bool, error) {
+	return DefaultContext().VerifyBlobKZGProof(blob, commitment, proof)
+}
+
+// VerifyBlobKZGProofBatch runs Context.VerifyBlobKZGProofBatch with the DefaultContext.
+func VerifyBlobKZGProofBatch(blobs BlobSequence, commitments KZGCommitmentSequence, proofs KZGProofSequence) (bool, error) {
+	return DefaultContext().VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+}
+
+// VerifyKZGProofFromPoints runs Context.VerifyKZGProofFromPoints with the DefaultContext.
+func VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
+	return DefaultContext().VerifyKZGProofFromPoints(polynomialKZG, z, y, kzgProof)
+}
+
+// VerifyKZGProofBatchFromPoints runs Context.VerifyKZGProofBatchFromPoints with the DefaultContext.
+func VerifyKZGProofBatchFromPoints(polynomialKZGs []bls.G1Point, zs []bls.Fr, ys []bls.Fr, kzgProofs []bls.G1Point) (bool, error) {
+	return DefaultContext().VerifyKZGProofBatchFromPoints(polynomialKZGs, zs, ys, kzgProofs)
+}
+
+// PolynomialToKZGCommitment runs Context.PolynomialToKZGCommitment with the DefaultContext.
+func PolynomialToKZGCommitment(eval Polynomial) KZGCommitment {
+	return DefaultContext().PolynomialToKZGCommitment(eval)
+}
+
+// ComputeKZGProofFromPolynomial runs Context.ComputeKZGProofFromPolynomial with the DefaultContext.
+func ComputeKZGProofFromPolynomial(polynomial []bls.Fr, z *bls.Fr) (KZGProof, *bls.Fr, error) {
+	return DefaultContext().ComputeKZGProofFromPolynomial(polynomial, z)
+}
+
+// EvaluatePolynomialInEvaluationForm runs Context.EvaluatePolynomialInEvaluationForm with the DefaultContext.
+func EvaluatePolynomialInEvaluationForm(poly []bls.Fr, x *bls.Fr) *bls.Fr {
+	return DefaultContext().EvaluatePolynomialInEvaluationForm(poly, x)
+}
+
+// BlobToPolynomial runs Context.BlobToPolynomial with the DefaultContext.
+func BlobToPolynomial(b Blob) (Polynomial, bool) {
+	return DefaultContext().BlobToPolynomial(b)
+}
+
+// BlobsToPolynomials runs Context.BlobsToPolynomials with the DefaultContext.
+func BlobsToPolynomials(blobs BlobSequence) ([][]bls.Fr, bool) {
+	return DefaultContext().BlobsToPolynomials(blobs)
+}
+
+// ComputeCells runs Context.ComputeCells with the DefaultContext.
+func ComputeCells(blob Blob) ([]Cell, error) {
+	return DefaultContext().ComputeCells(blob)
+}
+
+// ComputeCellsAndKZGProofs runs Context.ComputeCellsAndKZGProofs with the DefaultContext.
+func ComputeCellsAndKZGProofs(blob Blob) ([]Cell, []KZGProof, error) {
+	return DefaultContext().ComputeCellsAndKZGProofs(blob)
+}
+
+// VerifyCellKZGProofBatch runs Context.VerifyCellKZGProofBatch with the DefaultContext.
+func VerifyCellKZGProofBatch(commitments []KZGCommitment, cellIndices []uint64, cells []Cell, proofs []KZGProof) (bool, error) {
+	return DefaultContext().VerifyCellKZGProofBatch(commitments, cellIndices, cells, proofs)
+}
 
-	// Initialize the domain
-	// ROOT_OF_UNITY = pow(PRIMITIVE_ROOT, (MODULUS - 1) // WIDTH, MODULUS)
-	primitiveRoot := big.NewInt(7)
-	width := big.NewInt(int64(FieldElementsPerBlob))
-	exp := new(big.Int).Div(new(big.Int).Sub(BLSModulus, big.NewInt(1)), width)
-	rootOfUnity := new(big.Int).Exp(primitiveRoot, exp, BLSModulus)
-	DomainFr = make([]bls.Fr, FieldElementsPerBlob)
-	for i := 0; i < FieldElementsPerBlob; i++ {
-		// We reverse the bits of the index as specified in https://github.com/ethereum/consensus-specs/pull/3011
-		// This effectively permutes the order of the elements in Domain
-		reversedIndex := reverseBits(uint64(i), FieldElementsPerBlob)
-		domain := new(big.Int).Exp(rootOfUnity, big.NewInt(int64(reversedIndex)), BLSModulus)
-		_ = bigToFr(&DomainFr[i], domain)
-	}
-
-	// initialize the 64 bytes of precompile return data: field elements per blob, field modulus (big-endian uint256)
-	new(big.Int).SetUint64(FieldElementsPerBlob).FillBytes(precompileReturnValue[:32])
-	BLSModulus.FillBytes(precompileReturnValue[32:])
+// RecoverCellsAndKZGProofs runs Context.RecoverCellsAndKZGProofs with the DefaultContext.
+func RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([]Cell, []KZGProof, error) {
+	return DefaultContext().RecoverCellsAndKZGProofs(cellIndices, cells)
 }
@@ -11,11 +11,13 @@ import (
 	"math/big"
 	"math/bits"
 
+	kzg "github.com/protolambda/go-kzg"
 	"github.com/protolambda/go-kzg/bls"
 )
 
 const (
-	FIAT_SHAMIR_PROTOCOL_DOMAIN = "FSBLOBVERIFY_V1_"
+	FIAT_SHAMIR_PROTOCOL_DOMAIN       = "FSBLOBVERIFY_V1_"
+	RANDOM_CHALLENGE_KZG_BATCH_DOMAIN = "RCKZGBATCH___V1_"
 )
 
 type Polynomial []bls.Fr
@@ -50,40 +52,57 @@ func bitReversalPermutation(l []bls.G1Point) []bls.G1Point {
 	return out
 }
 
-// VerifyKZGProofFromPoints implements verify_kzg_proof from the EIP-4844 consensus spec,
+// VerifyKZGProofFromPoints implements verify_kzg_proof_impl from the EIP-4844 consensus spec,
 // only with the byte inputs already parsed into points & field elements.
-func VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
+func (ctx *Context) VerifyKZGProofFromPoints(polynomialKZG *bls.G1Point, z *bls.Fr, y *bls.Fr, kzgProof *bls.G1Point) bool {
 	var zG2 bls.G2Point
 	bls.MulG2(&zG2, &bls.GenG2, z)
 	var yG1 bls.G1Point
 	bls.MulG1(&yG1, &bls.GenG1, y)
 
 	var xMinusZ bls.G2Point
-	bls.SubG2(&xMinusZ, &kzgSetupG2[1], &zG2)
+	bls.SubG2(&xMinusZ, &ctx.setupG2[1], &zG2)
 	var pMinusY bls.G1Point
 	bls.SubG1(&pMinusY, polynomialKZG, &yG1)
 
 	return bls.PairingsVerify(&pMinusY, &bls.GenG2, kzgProof, &xMinusZ)
 }
 
-// VerifyAggregateKZGProofFromPolynomials implements verify_aggregate_kzg_proof from the EIP-4844 consensus spec,
-// only operating on blobs that have already been converted into polynomials.
-func VerifyAggregateKZGProofFromPolynomials(blobs Polynomials, expectedKZGCommitments KZGCommitmentSequence, kzgAggregatedProof KZGProof) (bool, error) {
-	aggregatedPoly, aggregatedPolyCommitment, evaluationChallenge, err :=
-		ComputeAggregatedPolyAndCommitment(blobs, expectedKZGCommitments)
-	if err != nil {
-		return false, err
-	}
-	y := EvaluatePolynomialInEvaluationForm(aggregatedPoly, evaluationChallenge)
-	kzgProofG1, err := bls.FromCompressedG1(kzgAggregatedProof[:])
-	if err != nil {
-		return false, fmt.Errorf("failed to decode kzgProof: %v", err)
+// VerifyKZGProofBatchFromPoints verifies many (commitment, z, y, proof) opening claims at once,
+// combining them with random challenges into a single pairing check, see kzg.CheckProofSingleBatch.
+// The returned error wraps kzg.ErrLengthMismatch if the input lengths do not match.
+func (ctx *Context) VerifyKZGProofBatchFromPoints(polynomialKZGs []bls.G1Point, zs []bls.Fr, ys []bls.Fr, kzgProofs []bls.G1Point) (bool, error) {
+	return kzg.CheckProofSingleBatchWithSetup(&ctx.setupG2[1], polynomialKZGs, kzgProofs, zs, ys)
+}
+
+// verifyKZGProofBatch implements verify_kzg_proof_batch from the EIP-4844 consensus spec,
+// only with the byte inputs already parsed into points & field elements.
+func (ctx *Context) verifyKZGProofBatch(polynomialKZGs []KZGCommitment, polynomialKZGsG1 []bls.G1Point, zs []bls.Fr, ys []bls.Fr, kzgProofs []KZGProof, kzgProofsG1 []bls.G1Point) (bool, error) {
+	n := len(polynomialKZGs)
+	sha := sha256.New()
+	sha.Write([]byte(RANDOM_CHALLENGE_KZG_BATCH_DOMAIN))
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], ctx.fieldElementsPerBlob)
+	sha.Write(tmp[:])
+	binary.BigEndian.PutUint64(tmp[:], uint64(n))
+	sha.Write(tmp[:])
+	for i := 0; i < n; i++ {
+		sha.Write(polynomialKZGs[i][:])
+		z := blsFieldToBytes(&zs[i])
+		sha.Write(z[:])
+		y := blsFieldToBytes(&ys[i])
+		sha.Write(y[:])
+		sha.Write(kzgProofs[i][:])
 	}
-	return VerifyKZGProofFromPoints(aggregatedPolyCommitment, evaluationChallenge, y, kzgProofG1), nil
+	var hash [32]byte
+	copy(hash[:], sha.Sum(nil))
+	r := hashToBLSField(hash)
+	rPowers := ComputePowers(r, n)
+	return kzg.CheckProofSingleBatchWithChallenges(&ctx.setupG2[1], polynomialKZGsG1, kzgProofsG1, zs, ys, rPowers)
 }
 
 // ComputePowers implements compute_powers from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_powers
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_powers
 func ComputePowers(r *bls.Fr, n int) []bls.Fr {
 	var currentPower bls.Fr
 	bls.AsFr(&currentPower, 1)
@@ -95,35 +114,51 @@ func ComputePowers(r *bls.Fr, n int) []bls.Fr {
 	return powers
 }
 
-func PolynomialToKZGCommitment(eval Polynomial) KZGCommitment {
-	g1 := bls.LinCombG1(kzgSetupLagrange, []bls.Fr(eval))
+func (ctx *Context) PolynomialToKZGCommitment(eval Polynomial) KZGCommitment {
+	g1 := bls.LinCombG1(ctx.setupLagrange, []bls.Fr(eval))
 	var out KZGCommitment
 	copy(out[:], bls.ToCompressedG1(g1))
 	return out
 }
 
 // bytesToBLSField implements bytes_to_bls_field from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#bytes_to_bls_field
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#bytes_to_bls_field
+//
+// The bytes are a big-endian integer, which must be less than the BLS modulus.
 func bytesToBLSField(element *bls.Fr, bytes32 [32]byte) bool {
+	// go-kzg expects little-endian
+	reverseArr32(&bytes32)
 	return bls.FrFrom32(element, bytes32)
 }
 
-// hashToBytesField implements hash_to_bls_field from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#hash_to_bls_field
-func hashToBLSField(input []byte) *bls.Fr {
-	// First hash the input -- For the fiat-shamir protocol this
-	// will be the compressed state with a challenge index appended
-	// to it.
+// blsFieldToBytes is the inverse of bytesToBLSField: it encodes the field element as big-endian integer.
+func blsFieldToBytes(element *bls.Fr) [32]byte {
+	out := bls.FrTo32(element)
+	reverseArr32(&out)
+	return out
+}
 
-	sha := sha256.New()
-	sha.Write(input[:])
+// bytesToKZGCommitment implements bytes_to_kzg_commitment from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#bytes_to_kzg_commitment
+//
+// The encoding is decoded strictly, see bls.DecodeCompressedG1. Like validate_kzg_g1,
+// the point at infinity is accepted, other points must be valid G1 points in the correct subgroup.
+func bytesToKZGCommitment(b KZGCommitment) (*bls.G1Point, error) {
+	return bls.DecodeCompressedG1(b[:], true)
+}
 
-	var hash32 [32]byte
-	copy(hash32[:], sha.Sum(nil))
+// bytesToKZGProof implements bytes_to_kzg_proof from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#bytes_to_kzg_proof
+//
+// The same rules as bytesToKZGCommitment apply: the proof of a constant polynomial is the point at infinity.
+func bytesToKZGProof(b KZGProof) (*bls.G1Point, error) {
+	return bls.DecodeCompressedG1(b[:], true)
+}
 
-	// Then interpret the hash digest as a little-endian integer
-	// modulo the bls field modulus
-	reverseArr32(&hash32)
+// hashToBLSField implements hash_to_bls_field from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#hash_to_bls_field
+func hashToBLSField(hash32 [32]byte) *bls.Fr {
+	// Interpret the hash digest as a big-endian integer modulo the bls field modulus
 	zB := new(big.Int).Mod(new(big.Int).SetBytes(hash32[:]), BLSModulus)
 
 	// Convert the big integer into a field element.
@@ -132,137 +167,105 @@ func hashToBLSField(input []byte) *bls.Fr {
 	return out
 }
 
-// ComputeAggregatedPolyAndCommitment implements compute_aggregated_poly_and_commitment from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_aggregated_poly_and_commitment
-func ComputeAggregatedPolyAndCommitment(blobs Polynomials, commitments KZGCommitmentSequence) ([]bls.Fr, *bls.G1Point, *bls.Fr, error) {
-	// create challenges
-	powers, evaluationChallenge, err := ComputeChallenges(blobs, commitments)
-	if err != nil {
-		return nil, nil, nil, err
-	}
+// computeChallenge implements compute_challenge from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_challenge
+func (ctx *Context) computeChallenge(blob Blob, commitment KZGCommitment) *bls.Fr {
+	sha := sha256.New()
+	sha.Write([]byte(FIAT_SHAMIR_PROTOCOL_DOMAIN))
 
-	aggregatedPoly, err := bls.PolyLinComb(blobs, powers, FieldElementsPerBlob)
-	if err != nil {
-		return nil, nil, nil, err
-	}
+	// degree of the polynomial, as 16-byte big-endian integer
+	var degreePoly [16]byte
+	binary.BigEndian.PutUint64(degreePoly[8:], ctx.fieldElementsPerBlob)
+	sha.Write(degreePoly[:])
 
-	l := commitments.Len()
-	commitmentsG1 := make([]bls.G1Point, l)
+	l := blob.Len()
 	for i := 0; i < l; i++ {
-		c := commitments.At(i)
-		p, err := bls.FromCompressedG1(c[:])
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		bls.CopyG1(&commitmentsG1[i], p)
+		fe := blob.At(i)
+		sha.Write(fe[:])
 	}
-	aggregatedCommitmentG1 := bls.LinCombG1(commitmentsG1, powers)
-	return aggregatedPoly, aggregatedCommitmentG1, evaluationChallenge, nil
-}
+	sha.Write(commitment[:])
 
-// ComputeAggregateKZGProofFromPolynomials implements compute_aggregate_kzg_proof from the EIP-4844
-// consensus spec, only operating over blobs that are already parsed into a polynomial.
-func ComputeAggregateKZGProofFromPolynomials(blobs Polynomials) (KZGProof, error) {
-	commitments := make(KZGCommitmentSequenceImpl, len(blobs))
-	for i, b := range blobs {
-		commitments[i] = PolynomialToKZGCommitment(Polynomial(b))
-	}
-	aggregatedPoly, _, evaluationChallenge, err := ComputeAggregatedPolyAndCommitment(blobs, commitments)
-	if err != nil {
-		return KZGProof{}, err
-	}
-	return ComputeKZGProof(aggregatedPoly, evaluationChallenge)
+	var hash [32]byte
+	copy(hash[:], sha.Sum(nil))
+	return hashToBLSField(hash)
 }
 
-// ComputeKZGProof implements compute_kzg_proof from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_kzg_proof
-func ComputeKZGProof(polynomial []bls.Fr, z *bls.Fr) (KZGProof, error) {
-	y := EvaluatePolynomialInEvaluationForm(polynomial, z)
+// ComputeKZGProofFromPolynomial implements compute_kzg_proof_impl from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_kzg_proof_impl
+//
+// It returns the proof and the evaluation y = p(z).
+func (ctx *Context) ComputeKZGProofFromPolynomial(polynomial []bls.Fr, z *bls.Fr) (KZGProof, *bls.Fr, error) {
+	if len(polynomial) != len(ctx.domain) {
+		return KZGProof{}, nil, errors.New("polynomial has invalid length")
+	}
+	y := ctx.EvaluatePolynomialInEvaluationForm(polynomial, z)
 	polynomialShifted := make([]bls.Fr, len(polynomial))
 	for i := range polynomial {
 		bls.SubModFr(&polynomialShifted[i], &polynomial[i], y)
 	}
 	denominatorPoly := make([]bls.Fr, len(polynomial))
-	if len(polynomial) != len(DomainFr) {
-		return KZGProof{}, errors.New("polynomial has invalid length")
-	}
 	for i := range polynomial {
-		if bls.EqualFr(&DomainFr[i], z) {
-			return KZGProof{}, errors.New("invalid z challenge")
-		}
-		bls.SubModFr(&denominatorPoly[i], &DomainFr[i], z)
+		bls.SubModFr(&denominatorPoly[i], &ctx.domain[i], z)
 	}
 	quotientPolynomial := make([]bls.Fr, len(polynomial))
 	for i := range polynomial {
-		bls.DivModFr(&quotientPolynomial[i], &polynomialShifted[i], &denominatorPoly[i])
+		if bls.EqualZero(&denominatorPoly[i]) {
+			// The denominator is zero hence z is a root of unity: we must handle it as a special case
+			ctx.computeQuotientEvalWithinDomain(&quotientPolynomial[i], &ctx.domain[i], polynomial, y)
+		} else {
+			// Compute: q(x_i) = (p(x_i) - p(z)) / (x_i - z).
+			bls.DivModFr(&quotientPolynomial[i], &polynomialShifted[i], &denominatorPoly[i])
+		}
 	}
-	rG1 := bls.LinCombG1(kzgSetupLagrange, quotientPolynomial)
+	rG1 := bls.LinCombG1(ctx.setupLagrange, quotientPolynomial)
 	var proof KZGProof
 	copy(proof[:], bls.ToCompressedG1(rG1))
-	return proof, nil
-}
-
-// EvaluatePolynomialInEvaluationForm implements evaluate_polynomial_in_evaluation_form from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#evaluate_polynomial_in_evaluation_form
-func EvaluatePolynomialInEvaluationForm(poly []bls.Fr, x *bls.Fr) *bls.Fr {
-	var result bls.Fr
-	bls.EvaluatePolyInEvaluationForm(&result, poly, x, DomainFr, 0)
-	return &result
+	return proof, y, nil
 }
 
-// ComputeChallenges implements compute_challenges from the EIP-4844 consensus spec:
-// https://github.com/ethereum/consensus-specs/blob/dev/specs/eip4844/polynomial-commitments.md#compute_challenges
-func ComputeChallenges(polys Polynomials, comms KZGCommitmentSequence) ([]bls.Fr, *bls.Fr, error) {
-	hash, err := hashPolysComms(polys, comms)
-	if err != nil {
-		return nil, nil, err
+// computeQuotientEvalWithinDomain implements compute_quotient_eval_within_domain from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#compute_quotient_eval_within_domain
+//
+// Given p(z) = y, with z a root of unity in the domain, it computes q(z) = sum_(i, w_i != z) (p(w_i) - y) * w_i / (z * (z - w_i)).
+func (ctx *Context) computeQuotientEvalWithinDomain(dst *bls.Fr, z *bls.Fr, polynomial []bls.Fr, y *bls.Fr) {
+	var result, fi, numerator, denominator, tmp bls.Fr
+	for i := range ctx.domain {
+		omegaI := &ctx.domain[i]
+		if bls.EqualFr(omegaI, z) {
+			continue
+		}
+		bls.SubModFr(&fi, &polynomial[i], y)
+		bls.MulModFr(&numerator, &fi, omegaI)
+		bls.SubModFr(&tmp, z, omegaI)
+		bls.MulModFr(&denominator, &tmp, z)
+		bls.DivModFr(&tmp, &numerator, &denominator)
+		bls.AddModFr(&result, &result, &tmp)
 	}
-
-	var linCombChallengeTranscript = append(hash[:], 0)
-	var evalChallengeTranscript = append(hash[:], 1)
-
-	linCombChallenge := hashToBLSField(linCombChallengeTranscript)
-	evalChallenge := hashToBLSField(evalChallengeTranscript)
-
-	rPowers := ComputePowers(linCombChallenge, len(polys))
-
-	return rPowers, evalChallenge, nil
-
+	bls.CopyFr(dst, &result)
 }
 
-// Adds the domain separator, polynomials and commitments into a buffer, returning the
-// hash of this buffer
-func hashPolysComms(polys Polynomials, comms KZGCommitmentSequence) ([32]byte, error) {
-	sha := sha256.New()
-	var hash [32]byte
-
-	sha.Write([]byte(FIAT_SHAMIR_PROTOCOL_DOMAIN))
-
-	bytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(bytes, uint64(FieldElementsPerBlob))
-	sha.Write(bytes)
-
-	bytes = make([]byte, 8)
-	binary.LittleEndian.PutUint64(bytes, uint64(len(polys)))
-	sha.Write(bytes)
-
-	for _, poly := range polys {
-		for _, fe := range poly {
-			b32 := bls.FrTo32(&fe)
-			sha.Write(b32[:])
+// EvaluatePolynomialInEvaluationForm implements evaluate_polynomial_in_evaluation_form from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#evaluate_polynomial_in_evaluation_form
+func (ctx *Context) EvaluatePolynomialInEvaluationForm(poly []bls.Fr, x *bls.Fr) *bls.Fr {
+	var result bls.Fr
+	// If we are asked to evaluate within the domain, we already know the answer
+	for i := range ctx.domain {
+		if bls.EqualFr(&ctx.domain[i], x) {
+			bls.CopyFr(&result, &poly[i])
+			return &result
 		}
 	}
-	l := comms.Len()
-	for i := 0; i < l; i++ {
-		c := comms.At(i)
-		sha.Write(c[:])
-	}
-	copy(hash[:], sha.Sum(nil))
-	return hash, nil
+	bls.EvaluatePolyInEvaluationForm(&result, poly, x, ctx.domain, 0)
+	return &result
 }
 
-func BlobToPolynomial(b Blob) (Polynomial, bool) {
+// BlobToPolynomial implements blob_to_polynomial from the EIP-4844 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md#blob_to_polynomial
+func (ctx *Context) BlobToPolynomial(b Blob) (Polynomial, bool) {
 	l := b.Len()
+	if uint64(l) != ctx.fieldElementsPerBlob {
+		return []bls.Fr{}, false
+	}
 	frs := make(Polynomial, l)
 	for i := 0; i < l; i++ {
 		if !bytesToBLSField(&frs[i], b.At(i)) {
@@ -272,11 +275,11 @@ func BlobToPolynomial(b Blob) (Polynomial, bool) {
 	return frs, true
 }
 
-func BlobsToPolynomials(blobs BlobSequence) ([][]bls.Fr, bool) {
+func (ctx *Context) BlobsToPolynomials(blobs BlobSequence) ([][]bls.Fr, bool) {
 	l := blobs.Len()
 	out := make(Polynomials, l)
 	for i := 0; i < l; i++ {
-		blob, ok := BlobToPolynomial(blobs.At(i))
+		blob, ok := ctx.BlobToPolynomial(blobs.At(i))
 		if !ok {
 			return nil, false
 		}
@@ -285,6 +288,19 @@ func BlobsToPolynomials(blobs BlobSequence) ([][]bls.Fr, bool) {
 	return out, true
 }
 
+// parses the commitment and the proof of a blob
+func parseBlobCommitmentAndProof(commitment KZGCommitment, proof KZGProof) (*bls.G1Point, *bls.G1Point, error) {
+	commitmentG1, err := bytesToKZGCommitment(commitment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode commitment: %v", err)
+	}
+	proofG1, err := bytesToKZGProof(proof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode proof: %v", err)
+	}
+	return commitmentG1, proofG1, nil
+}
+
 func bigToFr(out *bls.Fr, in *big.Int) bool {
 	// Convert big.Int to a 32 byte array
 	// Note that this function will panic if the
@@ -294,11 +310,9 @@ func bigToFr(out *bls.Fr, in *big.Int) bool {
 	inb := in.Bytes()
 	copy(b[32-len(inb):], inb)
 
-	// The byte array `b` is an integer in big endian format.
-	// We therefore need to reverse it as go-kzg expects little-endian
-	reverseArr32(&b)
-
-	return bls.FrFrom32(out, b)
+	// The byte array `b` is an integer in big endian format,
+	// which is exactly what bytes_to_bls_field expects.
+	return bytesToBLSField(out, b)
 }
 
 func reverseArr32(input *[32]byte) {
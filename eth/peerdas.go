@@ -0,0 +1,433 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	kzg "github.com/protolambda/go-kzg"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// PeerDAS (EIP-7594) extends every blob to twice its size, and splits the extension into cells,
+// each with a KZG multi-proof, as specified in the polynomial-commitments-sampling consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md
+const (
+	FieldElementsPerExtBlob = 2 * FieldElementsPerBlob
+	FieldElementsPerCell    = 64
+	BytesPerCell            = FieldElementsPerCell * BytesPerFieldElement
+	CellsPerExtBlob         = FieldElementsPerExtBlob / FieldElementsPerCell
+
+	RANDOM_CHALLENGE_KZG_CELL_BATCH_DOMAIN = "RCKZGCBATCH__V1_"
+)
+
+// Cell is a coset of FieldElementsPerCell evaluations of the extended blob polynomial,
+// each encoded as a big-endian field element.
+type Cell [FieldElementsPerCell][32]byte
+
+var (
+	errInvalidCell        = errors.New("invalid cell")
+	errInvalidCellIndex   = errors.New("invalid cell index")
+	errDuplicateCellIndex = errors.New("duplicate cell index")
+	errUnsortedCellIndex  = errors.New("cell indices not in ascending order")
+	errNotEnoughCells     = errors.New("not enough cells to recover")
+)
+
+// getPeerDASSettings lazily prepares the FFT settings of the extended domain and the FK20 multi-proof settings,
+// the precomputation is only worth doing when cell proofs are actually used.
+func (ctx *Context) getPeerDASSettings() (*kzg.FK20MultiSettings, error) {
+	ctx.peerDASOnce.Do(func() {
+		// FK20 needs the blob polynomial to span at least two cells
+		if ctx.fieldElementsPerBlob < 2*FieldElementsPerCell {
+			ctx.peerDASErr = fmt.Errorf("setup width %d is too small for cells of %d field elements", ctx.fieldElementsPerBlob, FieldElementsPerCell)
+			return
+		}
+		if ctx.setupG1 == nil {
+			ctx.peerDASErr = errors.New("trusted setup has no monomial G1 points, needed for cell proofs")
+			return
+		}
+		if len(ctx.setupG2) <= FieldElementsPerCell {
+			ctx.peerDASErr = fmt.Errorf("trusted setup has %d G2 points, need %d for cell proofs", len(ctx.setupG2), FieldElementsPerCell+1)
+			return
+		}
+		extWidth := 2 * ctx.fieldElementsPerBlob
+		fs := kzg.NewFFTSettings(uint8(bits.Len64(extWidth - 1)))
+		// The trusted setup does not have a G2 point for every G1 point,
+		// so this cannot use kzg.NewKZGSettings, which requires the setup to cover the full extended domain.
+		ks := &kzg.KZGSettings{
+			FFTSettings: fs,
+			SecretG1:    ctx.setupG1,
+			SecretG2:    ctx.setupG2,
+		}
+		ctx.peerDASSettings, ctx.peerDASErr = kzg.TryNewFK20MultiSettings(ks, extWidth, FieldElementsPerCell)
+	})
+	return ctx.peerDASSettings, ctx.peerDASErr
+}
+
+// CellsPerExtBlob returns the number of cells of an extended blob of the width of the setup of the context.
+func (ctx *Context) CellsPerExtBlob() uint64 {
+	return 2 * ctx.fieldElementsPerBlob / FieldElementsPerCell
+}
+
+// Return a copy of the input array permuted by bit-reversing the indexes.
+func bitReversalPermutationFr(l []bls.Fr) []bls.Fr {
+	out := make([]bls.Fr, len(l))
+
+	order := uint64(len(l))
+
+	for i := range l {
+		out[i] = l[reverseBits(uint64(i), order)]
+	}
+
+	return out
+}
+
+// polynomialEvalToCoeff implements polynomial_eval_to_coeff from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#polynomial_eval_to_coeff
+//
+// The evaluations are in the bit-reversed order of the blob domain.
+func polynomialEvalToCoeff(fs *kzg.FFTSettings, poly Polynomial) ([]bls.Fr, error) {
+	return fs.FFT(bitReversalPermutationFr(poly), true)
+}
+
+// cosetShiftForCell implements coset_shift_for_cell from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#coset_shift_for_cell
+//
+// Cell i covers the points h_i * w^j of the extended domain of fs, with w the FieldElementsPerCell-th root of unity.
+func cosetShiftForCell(fs *kzg.FFTSettings, cellIndex uint64) *bls.Fr {
+	return &fs.ExpandedRootsOfUnity[reverseBits(cellIndex, fs.MaxWidth/FieldElementsPerCell)]
+}
+
+// ComputeCells implements compute_cells from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells
+func (ctx *Context) ComputeCells(blob Blob) ([]Cell, error) {
+	ks, err := ctx.getPeerDASSettings()
+	if err != nil {
+		return nil, err
+	}
+	poly, ok := ctx.BlobToPolynomial(blob)
+	if !ok {
+		return nil, errInvalidBlob
+	}
+	coeffs, err := polynomialEvalToCoeff(ks.FFTSettings, poly)
+	if err != nil {
+		return nil, err
+	}
+	return computeCellsFromCoeffs(ks.FFTSettings, coeffs)
+}
+
+// ComputeCellsAndKZGProofs implements compute_cells_and_kzg_proofs from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_cells_and_kzg_proofs
+//
+// All CellsPerExtBlob proofs are computed at once with the FK20 multi-proof method.
+func (ctx *Context) ComputeCellsAndKZGProofs(blob Blob) ([]Cell, []KZGProof, error) {
+	ks, err := ctx.getPeerDASSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+	poly, ok := ctx.BlobToPolynomial(blob)
+	if !ok {
+		return nil, nil, errInvalidBlob
+	}
+	coeffs, err := polynomialEvalToCoeff(ks.FFTSettings, poly)
+	if err != nil {
+		return nil, nil, err
+	}
+	cells, err := computeCellsFromCoeffs(ks.FFTSettings, coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	proofs, err := computeCellProofsFromCoeffs(ks, coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cells, proofs, nil
+}
+
+// computeCellsFromCoeffs evaluates the polynomial over the extended domain, and splits the bit-reversed evaluations into cells.
+func computeCellsFromCoeffs(fs *kzg.FFTSettings, coeffs []bls.Fr) ([]Cell, error) {
+	extended := make([]bls.Fr, fs.MaxWidth)
+	copy(extended, coeffs)
+	evals, err := fs.FFT(extended, false)
+	if err != nil {
+		return nil, err
+	}
+	evals = bitReversalPermutationFr(evals)
+	cells := make([]Cell, fs.MaxWidth/FieldElementsPerCell)
+	for i := range cells {
+		for j := 0; j < FieldElementsPerCell; j++ {
+			cells[i][j] = blsFieldToBytes(&evals[i*FieldElementsPerCell+j])
+		}
+	}
+	return cells, nil
+}
+
+// computeCellProofsFromCoeffs computes the proofs of all cells, in cell order.
+func computeCellProofsFromCoeffs(ks *kzg.FK20MultiSettings, coeffs []bls.Fr) ([]KZGProof, error) {
+	// The FK20 proofs of the extended domain, in reverse bit order, are exactly in the order of the cells.
+	proofsG1, err := ks.TryDAUsingFK20Multi(coeffs)
+	if err != nil {
+		return nil, err
+	}
+	proofs := make([]KZGProof, len(proofsG1))
+	for i := range proofsG1 {
+		copy(proofs[i][:], bls.ToCompressedG1(&proofsG1[i]))
+	}
+	return proofs, nil
+}
+
+// RecoverCellsAndKZGProofs implements recover_cells_and_kzg_proofs from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#recover_cells_and_kzg_proofs
+//
+// Given any half or more of the cells of a blob, with their cell indices, it recovers all CellsPerExtBlob cells,
+// and recomputes all the cell proofs. The cell indices must be unique and in ascending order.
+func (ctx *Context) RecoverCellsAndKZGProofs(cellIndices []uint64, cells []Cell) ([]Cell, []KZGProof, error) {
+	if len(cellIndices) != len(cells) {
+		return nil, nil, fmt.Errorf("got %d cell indices but %d cells", len(cellIndices), len(cells))
+	}
+	cellsPerExtBlob := ctx.CellsPerExtBlob()
+	extWidth := cellsPerExtBlob * FieldElementsPerCell
+	if uint64(len(cells)) < cellsPerExtBlob/2 {
+		return nil, nil, fmt.Errorf("%w: got %d cells, need at least %d", errNotEnoughCells, len(cells), cellsPerExtBlob/2)
+	}
+	if uint64(len(cells)) > cellsPerExtBlob {
+		return nil, nil, fmt.Errorf("got %d cells, but a blob only has %d", len(cells), cellsPerExtBlob)
+	}
+	ks, err := ctx.getPeerDASSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The samples of the extended blob, in natural order of the extended domain, nil if missing.
+	samples := make([]*bls.Fr, extWidth)
+	present := make([]bool, cellsPerExtBlob)
+	for k, cellIndex := range cellIndices {
+		if cellIndex >= cellsPerExtBlob {
+			return nil, nil, fmt.Errorf("%w: cell %d has index %d", errInvalidCellIndex, k, cellIndex)
+		}
+		if k > 0 && cellIndex == cellIndices[k-1] {
+			return nil, nil, fmt.Errorf("%w: %d", errDuplicateCellIndex, cellIndex)
+		}
+		if k > 0 && cellIndex < cellIndices[k-1] {
+			return nil, nil, fmt.Errorf("%w: %d after %d", errUnsortedCellIndex, cellIndex, cellIndices[k-1])
+		}
+		present[cellIndex] = true
+		evals, ok := cellToCosetEvals(&cells[k])
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: cell %d", errInvalidCell, k)
+		}
+		for j := range evals {
+			i := reverseBits(cellIndex*FieldElementsPerCell+uint64(j), extWidth)
+			samples[i] = &evals[j]
+		}
+	}
+
+	// Cell i is the coset of the FFT domain with shift h_i = w^reverse_bits(i), i.e. coset number reverse_bits(i)
+	// when grouping the points with the same x^FieldElementsPerCell value. The zero polynomial vanishes on all of them.
+	var missingCosets []uint64
+	for cellIndex := uint64(0); cellIndex < cellsPerExtBlob; cellIndex++ {
+		if !present[cellIndex] {
+			missingCosets = append(missingCosets, reverseBits(cellIndex, cellsPerExtBlob))
+		}
+	}
+	zeroEval, zeroPoly, err := ks.ZeroPolyForCosets(missingCosets, FieldElementsPerCell, extWidth)
+	if err != nil {
+		return nil, nil, err
+	}
+	zeroPolyFn := func(missingIndices []uint64, length uint64) ([]bls.Fr, []bls.Fr) {
+		return zeroEval, zeroPoly
+	}
+	extended, err := ks.RecoverPolyFromSamples(samples, zeroPolyFn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to recover extended blob: %w", err)
+	}
+	coeffs, err := ks.FFT(extended, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The recovered polynomial is of the blob, the higher coefficients of the extension are zero.
+	coeffs = coeffs[:ctx.fieldElementsPerBlob]
+
+	outCells, err := computeCellsFromCoeffs(ks.FFTSettings, coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	proofs, err := computeCellProofsFromCoeffs(ks, coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return outCells, proofs, nil
+}
+
+// cellToCosetEvals implements cell_to_coset_evals from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#cell_to_coset_evals
+func cellToCosetEvals(cell *Cell) ([]bls.Fr, bool) {
+	out := make([]bls.Fr, FieldElementsPerCell)
+	for i := range cell {
+		if !bytesToBLSField(&out[i], cell[i]) {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// VerifyCellKZGProofBatch implements verify_cell_kzg_proof_batch from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+//
+// The cells may be of different blobs, commitments[k] is the commitment of the blob of cells[k].
+// All cells are checked with a single pairing check.
+func (ctx *Context) VerifyCellKZGProofBatch(commitments []KZGCommitment, cellIndices []uint64, cells []Cell, proofs []KZGProof) (bool, error) {
+	n := len(cells)
+	if len(commitments) != n || len(cellIndices) != n || len(proofs) != n {
+		return false, fmt.Errorf("batch input lengths don't match: %d commitments, %d cell indices, %d cells, %d proofs",
+			len(commitments), len(cellIndices), n, len(proofs))
+	}
+	cellsPerExtBlob := ctx.CellsPerExtBlob()
+	for k, cellIndex := range cellIndices {
+		if cellIndex >= cellsPerExtBlob {
+			return false, fmt.Errorf("%w: cell %d has index %d", errInvalidCellIndex, k, cellIndex)
+		}
+	}
+	ks, err := ctx.getPeerDASSettings()
+	if err != nil {
+		return false, err
+	}
+
+	// Deduplicate the commitments, in order of first occurrence
+	var uniqueCommitments []KZGCommitment
+	var uniqueCommitmentsG1 []bls.G1Point
+	commitmentIndices := make([]uint64, n)
+	seen := make(map[KZGCommitment]uint64)
+	for k, commitment := range commitments {
+		index, ok := seen[commitment]
+		if !ok {
+			commitmentG1, err := bytesToKZGCommitment(commitment)
+			if err != nil {
+				return false, fmt.Errorf("failed to decode commitment %d: %v", k, err)
+			}
+			index = uint64(len(uniqueCommitments))
+			seen[commitment] = index
+			uniqueCommitments = append(uniqueCommitments, commitment)
+			uniqueCommitmentsG1 = append(uniqueCommitmentsG1, *commitmentG1)
+		}
+		commitmentIndices[k] = index
+	}
+	cosetsEvals := make([][]bls.Fr, n)
+	for k := range cells {
+		evals, ok := cellToCosetEvals(&cells[k])
+		if !ok {
+			return false, fmt.Errorf("%w: cell %d", errInvalidCell, k)
+		}
+		cosetsEvals[k] = evals
+	}
+	proofsG1 := make([]bls.G1Point, n)
+	for k := range proofs {
+		proofG1, err := bytesToKZGProof(proofs[k])
+		if err != nil {
+			return false, fmt.Errorf("failed to decode proof %d: %v", k, err)
+		}
+		bls.CopyG1(&proofsG1[k], proofG1)
+	}
+	if n == 0 {
+		return true, nil
+	}
+	r := ctx.computeVerifyCellKZGProofBatchChallenge(uniqueCommitments, commitmentIndices, cellIndices, cosetsEvals, proofs)
+	return verifyCellKZGProofBatchImpl(ks.KZGSettings, uniqueCommitmentsG1, commitmentIndices, cellIndices, cosetsEvals, proofsG1, r)
+}
+
+// computeVerifyCellKZGProofBatchChallenge implements compute_verify_cell_kzg_proof_batch_challenge from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#compute_verify_cell_kzg_proof_batch_challenge
+func (ctx *Context) computeVerifyCellKZGProofBatchChallenge(commitments []KZGCommitment, commitmentIndices []uint64, cellIndices []uint64, cosetsEvals [][]bls.Fr, proofs []KZGProof) *bls.Fr {
+	sha := sha256.New()
+	sha.Write([]byte(RANDOM_CHALLENGE_KZG_CELL_BATCH_DOMAIN))
+	var tmp [8]byte
+	writeUint64 := func(v uint64) {
+		binary.BigEndian.PutUint64(tmp[:], v)
+		sha.Write(tmp[:])
+	}
+	writeUint64(ctx.fieldElementsPerBlob)
+	writeUint64(FieldElementsPerCell)
+	writeUint64(uint64(len(commitments)))
+	writeUint64(uint64(len(cellIndices)))
+	for i := range commitments {
+		sha.Write(commitments[i][:])
+	}
+	for k := range cosetsEvals {
+		writeUint64(commitmentIndices[k])
+		writeUint64(cellIndices[k])
+		for i := range cosetsEvals[k] {
+			b := blsFieldToBytes(&cosetsEvals[k][i])
+			sha.Write(b[:])
+		}
+		sha.Write(proofs[k][:])
+	}
+	var hash [32]byte
+	copy(hash[:], sha.Sum(nil))
+	return hashToBLSField(hash)
+}
+
+// verifyCellKZGProofBatchImpl implements verify_cell_kzg_proof_batch_impl from the EIP-7594 consensus spec:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch_impl
+//
+// With r_k the powers of the challenge, h_k the coset shifts and I_k the interpolation polynomials of the cells, it checks
+//
+//	e(sum_k r_k * [C_k - I_k(s) + h_k^n * proof_k], [1]) = e(sum_k r_k * [proof_k], [s^n])
+func verifyCellKZGProofBatchImpl(ks *kzg.KZGSettings, commitments []bls.G1Point, commitmentIndices []uint64, cellIndices []uint64,
+	cosetsEvals [][]bls.Fr, proofs []bls.G1Point, r *bls.Fr) (bool, error) {
+	n := len(cellIndices)
+	rPowers := ComputePowers(r, n)
+
+	// sum_k r_k * proof_k
+	proofLincomb := bls.LinCombG1(proofs, rPowers)
+
+	// sum_k r_k * h_k^n * proof_k
+	weightedRPowers := make([]bls.Fr, n)
+	for k := 0; k < n; k++ {
+		var hkPow bls.Fr
+		bls.ExpModFrUint64(&hkPow, cosetShiftForCell(ks.FFTSettings, cellIndices[k]), FieldElementsPerCell)
+		bls.MulModFr(&weightedRPowers[k], &rPowers[k], &hkPow)
+	}
+	proofLincombWeighted := bls.LinCombG1(proofs, weightedRPowers)
+
+	// sum_k r_k * C_k, grouped by commitment
+	commitmentWeights := make([]bls.Fr, len(commitments))
+	for k := 0; k < n; k++ {
+		w := &commitmentWeights[commitmentIndices[k]]
+		bls.AddModFr(w, w, &rPowers[k])
+	}
+	commWeighted := bls.LinCombG1(commitments, commitmentWeights)
+
+	// sum_k r_k * I_k(X)
+	sumInterpPoly := make([]bls.Fr, FieldElementsPerCell)
+	var tmp bls.Fr
+	for k := 0; k < n; k++ {
+		interpPoly, err := interpolateCosetEvals(ks.FFTSettings, cellIndices[k], cosetsEvals[k])
+		if err != nil {
+			return false, err
+		}
+		for i := range interpPoly {
+			bls.MulModFr(&tmp, &interpPoly[i], &rPowers[k])
+			bls.AddModFr(&sumInterpPoly[i], &sumInterpPoly[i], &tmp)
+		}
+	}
+	interp := bls.LinCombG1(ks.SecretG1[:FieldElementsPerCell], sumInterpPoly)
+
+	var rl, tmpG1 bls.G1Point
+	bls.SubG1(&tmpG1, commWeighted, interp)
+	bls.AddG1(&rl, &tmpG1, proofLincombWeighted)
+
+	return bls.PairingsVerify(&rl, &bls.GenG2, proofLincomb, &ks.SecretG2[FieldElementsPerCell]), nil
+}
+
+// interpolateCosetEvals returns the coefficients of the polynomial of degree < FieldElementsPerCell
+// that matches the (bit-reversed order) evaluations of the given cell.
+func interpolateCosetEvals(fs *kzg.FFTSettings, cellIndex uint64, cosetEvals []bls.Fr) ([]bls.Fr, error) {
+	// The cell evaluations, in natural order, are of the polynomial I(h*X) over the FieldElementsPerCell roots of unity.
+	// Interpolating over the coset h*w^i gives I(X) directly.
+	return fs.CosetIFFT(bitReversalPermutationFr(cosetEvals), kzg.NewCosetPowers(cosetShiftForCell(fs, cellIndex), FieldElementsPerCell))
+}
@@ -0,0 +1,378 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+	"gopkg.in/yaml.v3"
+)
+
+func decodeCell(s string) (Cell, error) {
+	var out Cell
+	b, err := decodeHex(s)
+	if err != nil {
+		return out, err
+	}
+	if len(b) != BytesPerCell {
+		return out, fmt.Errorf("expected %d bytes, got %d", BytesPerCell, len(b))
+	}
+	for i := range out {
+		copy(out[i][:], b[i*BytesPerFieldElement:(i+1)*BytesPerFieldElement])
+	}
+	return out, nil
+}
+
+func checkCells(t *testing.T, cells []Cell, expected []string) {
+	if len(cells) != len(expected) {
+		t.Fatalf("expected %d cells, got %d", len(expected), len(cells))
+	}
+	for i := range expected {
+		cell, err := decodeCell(expected[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cells[i] != cell {
+			t.Fatalf("cell %d mismatch", i)
+		}
+	}
+}
+
+func TestComputeCells(t *testing.T) {
+	runSpecTests(t, "compute_cells", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob string `yaml:"blob"`
+			} `yaml:"input"`
+			Output *[]string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := decodeBlob(test.Input.Blob)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		cells, err := ComputeCells(blob)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCells(t, cells, *test.Output)
+	})
+}
+
+func TestComputeCellsAndKZGProofs(t *testing.T) {
+	runSpecTests(t, "compute_cells_and_kzg_proofs", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob string `yaml:"blob"`
+			} `yaml:"input"`
+			Output *[][]string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		blob, err := decodeBlob(test.Input.Blob)
+		if err != nil {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+			return
+		}
+		cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCells(t, cells, (*test.Output)[0])
+		expectedProofs := (*test.Output)[1]
+		if len(proofs) != len(expectedProofs) {
+			t.Fatalf("expected %d proofs, got %d", len(expectedProofs), len(proofs))
+		}
+		for i := range expectedProofs {
+			proof, err := decodeBytes48(expectedProofs[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proofs[i] != proof {
+				t.Fatalf("proof %d mismatch: got %x, expected %x", i, proofs[i], proof)
+			}
+		}
+	})
+}
+
+func TestVerifyCellKZGProofBatch(t *testing.T) {
+	runSpecTests(t, "verify_cell_kzg_proof_batch", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Commitments []string `yaml:"commitments"`
+				CellIndices []uint64 `yaml:"cell_indices"`
+				Cells       []string `yaml:"cells"`
+				Proofs      []string `yaml:"proofs"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		decodeErr := func(err error) {
+			if test.Output != nil {
+				t.Fatalf("failed to decode valid input: %v", err)
+			}
+		}
+		var commitments []KZGCommitment
+		for _, s := range test.Input.Commitments {
+			commitment, err := decodeBytes48(s)
+			if err != nil {
+				decodeErr(err)
+				return
+			}
+			commitments = append(commitments, commitment)
+		}
+		var cells []Cell
+		for _, s := range test.Input.Cells {
+			cell, err := decodeCell(s)
+			if err != nil {
+				decodeErr(err)
+				return
+			}
+			cells = append(cells, cell)
+		}
+		var proofs []KZGProof
+		for _, s := range test.Input.Proofs {
+			proof, err := decodeBytes48(s)
+			if err != nil {
+				decodeErr(err)
+				return
+			}
+			proofs = append(proofs, proof)
+		}
+		ok, err := VerifyCellKZGProofBatch(commitments, test.Input.CellIndices, cells, proofs)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != *test.Output {
+			t.Fatalf("got %v, expected %v", ok, *test.Output)
+		}
+	})
+}
+
+func TestComputeVerifyCellKZGProofBatchChallenge(t *testing.T) {
+	runSpecTests(t, "compute_verify_cell_kzg_proof_batch_challenge", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Commitments       []string   `yaml:"commitments"`
+				CommitmentIndices []uint64   `yaml:"commitment_indices"`
+				CellIndices       []uint64   `yaml:"cell_indices"`
+				CosetsEvals       [][]string `yaml:"cosets_evals"`
+				Proofs            []string   `yaml:"proofs"`
+			} `yaml:"input"`
+			Output string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		commitments := make([]KZGCommitment, len(test.Input.Commitments))
+		for i, s := range test.Input.Commitments {
+			b, err := decodeBytes48(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			commitments[i] = b
+		}
+		cosetsEvals := make([][]bls.Fr, len(test.Input.CosetsEvals))
+		for k, evals := range test.Input.CosetsEvals {
+			cosetsEvals[k] = make([]bls.Fr, len(evals))
+			for i, s := range evals {
+				b, err := decodeBytes32(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytesToBLSField(&cosetsEvals[k][i], b) {
+					t.Fatalf("invalid field element %s", s)
+				}
+			}
+		}
+		proofs := make([]KZGProof, len(test.Input.Proofs))
+		for i, s := range test.Input.Proofs {
+			b, err := decodeBytes48(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proofs[i] = b
+		}
+		expected, err := decodeBytes32(test.Output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := DefaultContext().computeVerifyCellKZGProofBatchChallenge(commitments, test.Input.CommitmentIndices, test.Input.CellIndices, cosetsEvals, proofs)
+		if got := blsFieldToBytes(r); got != expected {
+			t.Fatalf("challenge mismatch: got %x, expected %x", got, expected)
+		}
+	})
+}
+
+func TestComputeCellsAndKZGProofsRoundTrip(t *testing.T) {
+	blob := make(testBlob, FieldElementsPerBlob)
+	for i := range blob {
+		blob[i][31] = byte(i)
+		blob[i][30] = byte(i >> 8)
+	}
+	commitment, ok := BlobToKZGCommitment(blob)
+	if !ok {
+		t.Fatal("invalid blob")
+	}
+	cells, proofs, err := ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first half of the extended blob is the blob itself
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		if cells[i/FieldElementsPerCell][i%FieldElementsPerCell] != blob[i] {
+			t.Fatalf("extended blob value %d does not match the blob", i)
+		}
+	}
+	indices := []uint64{0, 5, 64, 127}
+	commitments := make([]KZGCommitment, len(indices))
+	selectedCells := make([]Cell, len(indices))
+	selectedProofs := make([]KZGProof, len(indices))
+	for k, i := range indices {
+		commitments[k] = commitment
+		selectedCells[k] = cells[i]
+		selectedProofs[k] = proofs[i]
+	}
+	ok, err = VerifyCellKZGProofBatch(commitments, indices, selectedCells, selectedProofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected cell proofs to verify")
+	}
+	// a proof for another cell must not verify
+	selectedProofs[1] = proofs[6]
+	ok, err = VerifyCellKZGProofBatch(commitments, indices, selectedCells, selectedProofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected cell proofs with a swapped proof to fail")
+	}
+}
+
+func TestRecoverCellsAndKZGProofs(t *testing.T) {
+	runSpecTests(t, "recover_cells_and_kzg_proofs", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				CellIndices []uint64 `yaml:"cell_indices"`
+				Cells       []string `yaml:"cells"`
+			} `yaml:"input"`
+			Output *[][]string `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &test); err != nil {
+			t.Fatal(err)
+		}
+		var cells []Cell
+		for _, s := range test.Input.Cells {
+			cell, err := decodeCell(s)
+			if err != nil {
+				if test.Output != nil {
+					t.Fatalf("failed to decode valid input: %v", err)
+				}
+				return
+			}
+			cells = append(cells, cell)
+		}
+		recoveredCells, recoveredProofs, err := RecoverCellsAndKZGProofs(test.Input.CellIndices, cells)
+		if test.Output == nil {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCells(t, recoveredCells, (*test.Output)[0])
+		expectedProofs := (*test.Output)[1]
+		if len(recoveredProofs) != len(expectedProofs) {
+			t.Fatalf("expected %d proofs, got %d", len(expectedProofs), len(recoveredProofs))
+		}
+		for i := range expectedProofs {
+			proof, err := decodeBytes48(expectedProofs[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recoveredProofs[i] != proof {
+				t.Fatalf("proof %d mismatch: got %x, expected %x", i, recoveredProofs[i], proof)
+			}
+		}
+	})
+}
+
+func TestRecoverCellsAndKZGProofsBadInput(t *testing.T) {
+	blob := make(testBlob, FieldElementsPerBlob)
+	for i := range blob {
+		blob[i][31] = byte(i * 7)
+	}
+	cells, _, err := ComputeCellsAndKZGProofs(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices := make([]uint64, 0, CellsPerExtBlob)
+	for i := uint64(0); i < CellsPerExtBlob; i++ {
+		indices = append(indices, i)
+	}
+	if _, _, err := RecoverCellsAndKZGProofs(indices[:CellsPerExtBlob/2-1], cells[:CellsPerExtBlob/2-1]); !errors.Is(err, errNotEnoughCells) {
+		t.Fatalf("expected not enough cells error, got: %v", err)
+	}
+	dupIndices := append([]uint64{}, indices[:CellsPerExtBlob/2]...)
+	dupIndices[10] = dupIndices[9]
+	if _, _, err := RecoverCellsAndKZGProofs(dupIndices, cells[:CellsPerExtBlob/2]); !errors.Is(err, errDuplicateCellIndex) {
+		t.Fatalf("expected duplicate cell index error, got: %v", err)
+	}
+	// a random selection of half the cells, in ascending order
+	rng := rand.New(rand.NewSource(1234))
+	perm := rng.Perm(CellsPerExtBlob)[:CellsPerExtBlob/2]
+	sort.Ints(perm)
+	someIndices := make([]uint64, len(perm))
+	someCells := make([]Cell, len(perm))
+	for k, i := range perm {
+		someIndices[k] = uint64(i)
+		someCells[k] = cells[i]
+	}
+	recovered, _, err := RecoverCellsAndKZGProofs(someIndices, someCells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range cells {
+		if recovered[i] != cells[i] {
+			t.Fatalf("recovered cell %d does not match", i)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package kzg
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// evalDomain returns the stride in the expanded roots of unity of the domain of a polynomial in evaluation form
+// of the given length.
+func (fs *FFTSettings) evalDomain(n uint64) (stride uint64, err error) {
+	if !bls.IsPowerOfTwo(n) {
+		return 0, fmt.Errorf("%w: got %d evaluations", ErrNotPowerOfTwo, n)
+	}
+	if n > fs.MaxWidth {
+		return 0, fmt.Errorf("%w: got %d evaluations but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
+	}
+	return fs.MaxWidth / n, nil
+}
+
+// EvaluateEvalPoly evaluates the polynomial in evaluation form, over the roots of unity of the length of eval
+// in natural order, at the point z, which may be inside or outside of the domain.
+func (fs *FFTSettings) EvaluateEvalPoly(eval []bls.Fr, z *bls.Fr) (*bls.Fr, error) {
+	stride, err := fs.evalDomain(uint64(len(eval)))
+	if err != nil {
+		return nil, err
+	}
+	var out bls.Fr
+	for i := range eval {
+		if bls.EqualFr(z, &fs.ExpandedRootsOfUnity[uint64(i)*stride]) {
+			bls.CopyFr(&out, &eval[i])
+			return &out, nil
+		}
+	}
+	scale := uint8(bits.Len64(stride) - 1)
+	bls.EvaluatePolyInEvaluationForm(&out, eval, z, fs.ExpandedRootsOfUnity[:fs.MaxWidth], scale)
+	return &out, nil
+}
+
+// ComputeEvalPolyQuotient computes the quotient q(X) = (p(X) - p(z)) / (X - z) in evaluation form,
+// directly from the polynomial p in evaluation form, over the roots of unity of the length of eval in natural order.
+// The point z may be inside of the domain. It also returns the evaluation y = p(z).
+func (fs *FFTSettings) ComputeEvalPolyQuotient(eval []bls.Fr, z *bls.Fr) (quotient []bls.Fr, y *bls.Fr, err error) {
+	y, err = fs.EvaluateEvalPoly(eval, z)
+	if err != nil {
+		return nil, nil, err
+	}
+	stride, _ := fs.evalDomain(uint64(len(eval)))
+	// q(w_i) = (p(w_i) - y) / (w_i - z)
+	quotient = make([]bls.Fr, len(eval), len(eval))
+	denoms := make([]bls.Fr, len(eval), len(eval))
+	inDomain := -1
+	for i := range eval {
+		bls.SubModFr(&denoms[i], &fs.ExpandedRootsOfUnity[uint64(i)*stride], z)
+		if bls.EqualZero(&denoms[i]) {
+			inDomain = i
+			// excluded from the batch inversion
+			bls.CopyFr(&denoms[i], &bls.ONE)
+		}
+	}
+	bls.BatchInvModFr(denoms)
+	var tmp bls.Fr
+	for i := range eval {
+		bls.SubModFr(&tmp, &eval[i], y)
+		bls.MulModFr(&quotient[i], &tmp, &denoms[i])
+	}
+	if inDomain >= 0 {
+		// z = w_m is a root of unity, then q(w_m) = sum_(i != m) (p(w_i) - y) * w_i / (w_m * (w_m - w_i))
+		//                                         = -1/w_m * sum_(i != m) q(w_i) * w_i
+		var sum, term bls.Fr
+		bls.CopyFr(&sum, &bls.ZERO)
+		for i := range eval {
+			if i == inDomain {
+				continue
+			}
+			bls.MulModFr(&term, &quotient[i], &fs.ExpandedRootsOfUnity[uint64(i)*stride])
+			bls.AddModFr(&tmp, &sum, &term)
+			bls.CopyFr(&sum, &tmp)
+		}
+		bls.DivModFr(&tmp, &sum, z)
+		bls.SubModFr(&quotient[inDomain], &bls.ZERO, &tmp)
+	}
+	return quotient, y, nil
+}
@@ -0,0 +1,60 @@
+package kzg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestComputeEvalPolyQuotient(t *testing.T) {
+	fs := NewFFTSettings(5)
+	for _, width := range []uint64{2, 8, 32} {
+		polynomial := make([]bls.Fr, width)
+		for i := range polynomial {
+			bls.AsFr(&polynomial[i], uint64(i*i+3))
+		}
+		evalPoly, err := fs.FFT(polynomial, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stride := fs.MaxWidth / width
+		var outside bls.Fr
+		bls.AsFr(&outside, 5431)
+		points := map[string]*bls.Fr{
+			"outside":     &outside,
+			"first_root":  &fs.ExpandedRootsOfUnity[0],
+			"second_root": &fs.ExpandedRootsOfUnity[stride],
+			"last_root":   &fs.ExpandedRootsOfUnity[(width-1)*stride],
+		}
+		for name, z := range points {
+			t.Run(fmt.Sprintf("width_%d_%s", width, name), func(t *testing.T) {
+				quotient, y, err := fs.ComputeEvalPolyQuotient(evalPoly, z)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var expectedY bls.Fr
+				bls.EvalPolyAt(&expectedY, polynomial, z)
+				if !bls.EqualFr(y, &expectedY) {
+					t.Fatalf("got y %s, expected %s", bls.FrStr(y), bls.FrStr(&expectedY))
+				}
+				// the quotient in coefficient form, padded to the width
+				expectedQuotient := make([]bls.Fr, width)
+				for i := range expectedQuotient {
+					bls.CopyFr(&expectedQuotient[i], &bls.ZERO)
+				}
+				for i, c := range Poly(polynomial).DivLinear(z) {
+					bls.CopyFr(&expectedQuotient[i], &c)
+				}
+				expectedEval, err := fs.FFT(expectedQuotient, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkPolyEqual(t, quotient, expectedEval)
+			})
+		}
+	}
+	if _, _, err := fs.ComputeEvalPolyQuotient(make([]bls.Fr, 3), &bls.ONE); err == nil {
+		t.Fatal("expected an error for a length that is not a power of two")
+	}
+}
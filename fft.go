@@ -39,6 +39,8 @@ type FFTSettings struct {
 	ExpandedRootsOfUnity []bls.Fr
 	// reverse domain, same as inverse values of domain. Also starting and ending with 1.
 	ReverseRootsOfUnity []bls.Fr
+	// the implementation used by FFT and InplaceFFT, the parallel variants are always recursive.
+	Algorithm FFTAlgorithm
 }
 
 func NewFFTSettings(maxScale uint8) *FFTSettings {
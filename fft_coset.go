@@ -0,0 +1,92 @@
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// CosetPowers holds the precomputed powers of a coset shift, to evaluate and interpolate polynomials
+// over the coset shift * w^i of the roots of unity, instead of over the roots of unity themselves.
+type CosetPowers struct {
+	Shift bls.Fr
+	// Powers[i] = shift^i
+	Powers []bls.Fr
+	// InvPowers[i] = shift^-i
+	InvPowers []bls.Fr
+}
+
+// NewCosetPowers precomputes the first n powers of the shift, and of its inverse. The shift must not be zero.
+func NewCosetPowers(shift *bls.Fr, n uint64) *CosetPowers {
+	c := &CosetPowers{
+		Powers:    make([]bls.Fr, n, n),
+		InvPowers: make([]bls.Fr, n, n),
+	}
+	bls.CopyFr(&c.Shift, shift)
+	var invShift bls.Fr
+	bls.InvModFr(&invShift, shift)
+	for i := uint64(0); i < n; i++ {
+		if i == 0 {
+			bls.CopyFr(&c.Powers[0], &bls.ONE)
+			bls.CopyFr(&c.InvPowers[0], &bls.ONE)
+			continue
+		}
+		bls.MulModFr(&c.Powers[i], &c.Powers[i-1], shift)
+		bls.MulModFr(&c.InvPowers[i], &c.InvPowers[i-1], &invShift)
+	}
+	return c
+}
+
+// scale multiplies each coefficient i with the i-th power of the shift, or of the inverse shift, in-place.
+func (c *CosetPowers) scale(poly []bls.Fr, inv bool) error {
+	if len(poly) > len(c.Powers) {
+		return fmt.Errorf("%w: got %d values but only have %d coset powers", ErrDomainTooSmall, len(poly), len(c.Powers))
+	}
+	powers := c.Powers
+	if inv {
+		powers = c.InvPowers
+	}
+	var tmp bls.Fr
+	for i := range poly {
+		bls.MulModFr(&tmp, &poly[i], &powers[i])
+		bls.CopyFr(&poly[i], &tmp)
+	}
+	return nil
+}
+
+// CosetFFT evaluates the polynomial in coefficient form over the coset: out[i] = poly(shift * w^i).
+func (fs *FFTSettings) CosetFFT(poly []bls.Fr, coset *CosetPowers) ([]bls.Fr, error) {
+	n := uint64(len(poly))
+	if n > fs.MaxWidth {
+		return nil, fmt.Errorf("%w: got %d values but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
+	}
+	shifted := make([]bls.Fr, n, n)
+	for i := range poly {
+		bls.CopyFr(&shifted[i], &poly[i])
+	}
+	if err := coset.scale(shifted, false); err != nil {
+		return nil, err
+	}
+	return fs.FFT(shifted, false)
+}
+
+// CosetIFFT interpolates the evaluations over the coset, evals[i] = poly(shift * w^i), into coefficient form.
+// It is the inverse of CosetFFT.
+func (fs *FFTSettings) CosetIFFT(evals []bls.Fr, coset *CosetPowers) ([]bls.Fr, error) {
+	poly, err := fs.FFT(evals, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := coset.scale(poly, true); err != nil {
+		return nil, err
+	}
+	return poly, nil
+}
+
+// the coset that RecoverPolyFromSamples moves to, away from the zero points of the zero polynomial.
+func newRecoveryCoset(n uint64) *CosetPowers {
+	var shift bls.Fr
+	bls.AsFr(&shift, 5) // primitive root of unity
+	bls.InvModFr(&shift, &shift)
+	return NewCosetPowers(&shift, n)
+}
@@ -0,0 +1,83 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCosetFFT(t *testing.T) {
+	fs := NewFFTSettings(5)
+	var shift bls.Fr
+	bls.AsFr(&shift, 7)
+	coset := NewCosetPowers(&shift, fs.MaxWidth)
+	for _, scale := range []uint8{0, 1, 3, 5} {
+		n := uint64(1) << scale
+		t.Run(fmt.Sprintf("scale_%d", scale), func(t *testing.T) {
+			poly := make([]bls.Fr, n, n)
+			for i := uint64(0); i < n; i++ {
+				bls.AsFr(&poly[i], i*i+3)
+			}
+			evals, err := fs.CosetFFT(poly, coset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stride := fs.MaxWidth / n
+			for i := uint64(0); i < n; i++ {
+				var x, expected bls.Fr
+				bls.MulModFr(&x, &shift, &fs.ExpandedRootsOfUnity[i*stride])
+				bls.EvalPolyAt(&expected, poly, &x)
+				if !bls.EqualFr(&evals[i], &expected) {
+					t.Fatalf("evaluation %d: got %s, expected %s", i, bls.FrStr(&evals[i]), bls.FrStr(&expected))
+				}
+			}
+			back, err := fs.CosetIFFT(evals, coset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range poly {
+				if !bls.EqualFr(&back[i], &poly[i]) {
+					t.Fatalf("coefficient %d: got %s, expected %s", i, bls.FrStr(&back[i]), bls.FrStr(&poly[i]))
+				}
+			}
+		})
+	}
+	if _, err := fs.CosetFFT(make([]bls.Fr, 64), coset); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+	if _, err := fs.CosetIFFT(make([]bls.Fr, 16), NewCosetPowers(&shift, 8)); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error for a short power table, got: %v", err)
+	}
+}
+
+func TestShiftPoly(t *testing.T) {
+	fs := NewFFTSettings(4)
+	poly := make([]bls.Fr, 16, 16)
+	for i := range poly {
+		bls.AsFr(&poly[i], uint64(i+1))
+	}
+	shifted := make([]bls.Fr, 16, 16)
+	for i := range poly {
+		bls.CopyFr(&shifted[i], &poly[i])
+	}
+	fs.ShiftPoly(shifted)
+	// p(x/5) at x=2
+	var x, invFive, xShifted, expected, got bls.Fr
+	bls.AsFr(&x, 2)
+	bls.AsFr(&invFive, 5)
+	bls.InvModFr(&invFive, &invFive)
+	bls.MulModFr(&xShifted, &x, &invFive)
+	bls.EvalPolyAt(&expected, poly, &xShifted)
+	bls.EvalPolyAt(&got, shifted, &x)
+	if !bls.EqualFr(&got, &expected) {
+		t.Fatalf("shifted poly mismatch: got %s, expected %s", bls.FrStr(&got), bls.FrStr(&expected))
+	}
+	fs.UnshiftPoly(shifted)
+	for i := range poly {
+		if !bls.EqualFr(&shifted[i], &poly[i]) {
+			t.Fatalf("coefficient %d: got %s, expected %s", i, bls.FrStr(&shifted[i]), bls.FrStr(&poly[i]))
+		}
+	}
+}
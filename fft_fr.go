@@ -53,9 +53,13 @@ func (fs *FFTSettings) _fft(vals []bls.Fr, valsOffset uint64, valsStride uint64,
 }
 
 func (fs *FFTSettings) FFT(vals []bls.Fr, inv bool) ([]bls.Fr, error) {
+	return fs.fft(vals, inv, 1)
+}
+
+func (fs *FFTSettings) fft(vals []bls.Fr, inv bool, workers int) ([]bls.Fr, error) {
 	n := uint64(len(vals))
 	if n > fs.MaxWidth {
-		return nil, fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+		return nil, fmt.Errorf("%w: got %d values but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
 	}
 	n = nextPowOf2(n)
 	// We make a copy so we can mutate it during the work.
@@ -67,19 +71,29 @@ func (fs *FFTSettings) FFT(vals []bls.Fr, inv bool) ([]bls.Fr, error) {
 		bls.CopyFr(&valsCopy[i], &bls.ZERO)
 	}
 	out := make([]bls.Fr, n, n)
-	if err := fs.InplaceFFT(valsCopy, out, inv); err != nil {
+	if err := fs.inplaceFFT(valsCopy, out, inv, workers); err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
 func (fs *FFTSettings) InplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool) error {
+	return fs.inplaceFFT(vals, out, inv, 1)
+}
+
+func (fs *FFTSettings) inplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool, workers int) error {
 	n := uint64(len(vals))
 	if n > fs.MaxWidth {
-		return fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+		return fmt.Errorf("%w: got %d values but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
 	}
 	if !bls.IsPowerOfTwo(n) {
-		return fmt.Errorf("got %d values but not a power of two", n)
+		return fmt.Errorf("%w: got %d values", ErrNotPowerOfTwo, n)
+	}
+	if fs.Algorithm != FFTRecursive && workers <= 1 {
+		for i := range vals {
+			bls.CopyFr(&out[i], &vals[i])
+		}
+		return fs.IterativeInplaceFFT(out[:n], inv, fs.Algorithm == FFTIterativeRadix4)
 	}
 	if inv {
 		var invLen bls.Fr
@@ -88,7 +102,7 @@ func (fs *FFTSettings) InplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool) error {
 		rootz := fs.ReverseRootsOfUnity[:fs.MaxWidth]
 		stride := fs.MaxWidth / n
 
-		fs._fft(vals, 0, 1, rootz, stride, out)
+		fs._fftParallel(vals, 0, 1, rootz, stride, out, workers)
 		var tmp bls.Fr
 		for i := 0; i < len(out); i++ {
 			bls.MulModFr(&tmp, &out[i], &invLen)
@@ -99,7 +113,7 @@ func (fs *FFTSettings) InplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool) error {
 		rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
 		stride := fs.MaxWidth / n
 		// Regular FFT
-		fs._fft(vals, 0, 1, rootz, stride, out)
+		fs._fftParallel(vals, 0, 1, rootz, stride, out, workers)
 		return nil
 	}
 }
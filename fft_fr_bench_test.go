@@ -7,7 +7,12 @@ import (
 )
 
 func benchFFT(scale uint8, b *testing.B) {
+	benchFFTAlgorithm(scale, FFTRecursive, b)
+}
+
+func benchFFTAlgorithm(scale uint8, alg FFTAlgorithm, b *testing.B) {
 	fs := NewFFTSettings(scale)
+	fs.Algorithm = alg
 	data := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
 	for i := uint64(0); i < fs.MaxWidth; i++ {
 		bls.CopyFr(&data[i], bls.RandomFr())
@@ -31,3 +36,64 @@ func BenchmarkFFTSettings_FFT(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkFFTSettings_FFTAlgorithm(b *testing.B) {
+	for _, alg := range []FFTAlgorithm{FFTRecursive, FFTIterativeRadix2, FFTIterativeRadix4} {
+		for scale := uint8(4); scale < 16; scale++ {
+			b.Run(fmt.Sprintf("%s/scale_%d", alg, scale), func(b *testing.B) {
+				benchFFTAlgorithm(scale, alg, b)
+			})
+		}
+	}
+}
+
+func benchIterativeInplaceFFT(scale uint8, radix4 bool, b *testing.B) {
+	fs := NewFFTSettings(scale)
+	data := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
+	for i := uint64(0); i < fs.MaxWidth; i++ {
+		bls.CopyFr(&data[i], bls.RandomFr())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// transforming the output of the previous iteration is as good as any input, and avoids allocations
+		if err := fs.IterativeInplaceFFT(data, false, radix4); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFFTSettings_IterativeInplaceFFT(b *testing.B) {
+	for _, radix4 := range []bool{false, true} {
+		for scale := uint8(4); scale < 16; scale++ {
+			b.Run(fmt.Sprintf("radix4_%v/scale_%d", radix4, scale), func(b *testing.B) {
+				benchIterativeInplaceFFT(scale, radix4, b)
+			})
+		}
+	}
+}
+
+func benchParallelFFT(scale uint8, b *testing.B) {
+	fs := NewFFTSettings(scale)
+	data := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
+	for i := uint64(0); i < fs.MaxWidth; i++ {
+		bls.CopyFr(&data[i], bls.RandomFr())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out, err := fs.ParallelFFT(data, false, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(out) != len(data) {
+			panic("output len doesn't match input")
+		}
+	}
+}
+
+func BenchmarkFFTSettings_ParallelFFT(b *testing.B) {
+	for scale := uint8(4); scale < 16; scale++ {
+		b.Run(fmt.Sprintf("scale_%d", scale), func(b *testing.B) {
+			benchParallelFFT(scale, b)
+		})
+	}
+}
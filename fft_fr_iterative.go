@@ -0,0 +1,123 @@
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// FFTAlgorithm selects the implementation of FFT and InplaceFFT over Fr.
+type FFTAlgorithm uint8
+
+const (
+	// FFTRecursive is the default recursive, out-of-place, FFT.
+	FFTRecursive FFTAlgorithm = iota
+	// FFTIterativeRadix2 is the iterative in-place FFT, with one butterfly layer per pass.
+	FFTIterativeRadix2
+	// FFTIterativeRadix4 is the iterative in-place FFT, with two butterfly layers fused per pass.
+	FFTIterativeRadix4
+)
+
+func (a FFTAlgorithm) String() string {
+	switch a {
+	case FFTRecursive:
+		return "recursive"
+	case FFTIterativeRadix2:
+		return "iterative_radix2"
+	case FFTIterativeRadix4:
+		return "iterative_radix4"
+	default:
+		return fmt.Sprintf("FFTAlgorithm(%d)", uint8(a))
+	}
+}
+
+// IterativeInplaceFFT runs the FFT over the values in place: the values are permuted in reverse bit order,
+// and then combined with layers of butterflies, with the roots of unity of the settings as twiddle factors.
+// With radix4 two layers are fused into one pass over the values. The results are the same as FFT.
+func (fs *FFTSettings) IterativeInplaceFFT(vals []bls.Fr, inv bool, radix4 bool) error {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return fmt.Errorf("%w: got %d values but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("%w: got %d values", ErrNotPowerOfTwo, n)
+	}
+	rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
+	if inv {
+		rootz = fs.ReverseRootsOfUnity[:fs.MaxWidth]
+	}
+	reverseBitOrderFr(vals)
+
+	// the size of the blocks that are already transformed
+	m := uint64(1)
+	if radix4 {
+		// an odd number of layers starts with a single radix-2 layer
+		if n > 1 && bitIndex(uint32(n))%2 == 1 {
+			fs.butterflyLayer(vals, rootz, 1)
+			m = 2
+		}
+		for ; m < n; m <<= 2 {
+			fs.butterflyLayer4(vals, rootz, m)
+		}
+	} else {
+		for ; m < n; m <<= 1 {
+			fs.butterflyLayer(vals, rootz, m)
+		}
+	}
+
+	if inv {
+		var invLen bls.Fr
+		bls.AsFr(&invLen, n)
+		bls.InvModFr(&invLen, &invLen)
+		var tmp bls.Fr
+		for i := range vals {
+			bls.MulModFr(&tmp, &vals[i], &invLen)
+			bls.CopyFr(&vals[i], &tmp)
+		}
+	}
+	return nil
+}
+
+// butterflyLayer combines pairs of transformed blocks of size half into blocks of twice the size.
+func (fs *FFTSettings) butterflyLayer(vals []bls.Fr, rootz []bls.Fr, half uint64) {
+	n := uint64(len(vals))
+	stride := fs.MaxWidth / (half << 1)
+	var x, yTimesRoot bls.Fr
+	for k := uint64(0); k < n; k += half << 1 {
+		for j := uint64(0); j < half; j++ {
+			bls.CopyFr(&x, &vals[k+j])
+			bls.MulModFr(&yTimesRoot, &vals[k+j+half], &rootz[j*stride])
+			bls.AddModFr(&vals[k+j], &x, &yTimesRoot)
+			bls.SubModFr(&vals[k+j+half], &x, &yTimesRoot)
+		}
+	}
+}
+
+// butterflyLayer4 combines groups of four transformed blocks of size quarter into blocks of four times the size,
+// i.e. two radix-2 layers, with the four values of each butterfly kept together.
+func (fs *FFTSettings) butterflyLayer4(vals []bls.Fr, rootz []bls.Fr, quarter uint64) {
+	n := uint64(len(vals))
+	stride2 := fs.MaxWidth / (quarter << 1)
+	stride4 := fs.MaxWidth / (quarter << 2)
+	var a0, a1, a2, a3, t bls.Fr
+	for k := uint64(0); k < n; k += quarter << 2 {
+		for j := uint64(0); j < quarter; j++ {
+			i0, i1, i2, i3 := k+j, k+j+quarter, k+j+2*quarter, k+j+3*quarter
+			// first layer: (i0, i1) and (i2, i3), blocks of 2*quarter
+			w := &rootz[j*stride2]
+			bls.MulModFr(&t, &vals[i1], w)
+			bls.AddModFr(&a0, &vals[i0], &t)
+			bls.SubModFr(&a1, &vals[i0], &t)
+			bls.MulModFr(&t, &vals[i3], w)
+			bls.AddModFr(&a2, &vals[i2], &t)
+			bls.SubModFr(&a3, &vals[i2], &t)
+			// second layer: (i0, i2) and (i1, i3), blocks of 4*quarter
+			bls.MulModFr(&t, &a2, &rootz[j*stride4])
+			bls.AddModFr(&vals[i0], &a0, &t)
+			bls.SubModFr(&vals[i2], &a0, &t)
+			bls.MulModFr(&t, &a3, &rootz[(j+quarter)*stride4])
+			bls.AddModFr(&vals[i1], &a1, &t)
+			bls.SubModFr(&vals[i3], &a1, &t)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package kzg
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/protolambda/go-kzg/bls"
@@ -97,3 +98,37 @@ func TestEvaluatePolyInEvaluationForm(t *testing.T) {
 		}
 	}
 }
+
+func TestIterativeFFT(t *testing.T) {
+	fs := NewFFTSettings(10)
+	for scale := uint8(0); scale <= 10; scale++ {
+		n := uint64(1) << scale
+		data := make([]bls.Fr, n, n)
+		for i := uint64(0); i < n; i++ {
+			bls.AsFr(&data[i], i*i+3)
+		}
+		for _, inv := range []bool{false, true} {
+			expected, err := fs.FFT(data, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, alg := range []FFTAlgorithm{FFTIterativeRadix2, FFTIterativeRadix4} {
+				algFs := *fs
+				algFs.Algorithm = alg
+				got, err := algFs.FFT(data, inv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := range expected {
+					if !bls.EqualFr(&got[i], &expected[i]) {
+						t.Fatalf("%s scale %d inv %v: difference at %d: got %s, expected %s",
+							alg, scale, inv, i, bls.FrStr(&got[i]), bls.FrStr(&expected[i]))
+					}
+				}
+			}
+		}
+	}
+	if err := fs.IterativeInplaceFFT(make([]bls.Fr, 3), false, false); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not a power of two error, got: %v", err)
+	}
+}
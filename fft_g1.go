@@ -56,12 +56,16 @@ func (fs *FFTSettings) _fftG1(vals []bls.G1Point, valsOffset uint64, valsStride
 }
 
 func (fs *FFTSettings) FFTG1(vals []bls.G1Point, inv bool) ([]bls.G1Point, error) {
+	return fs.fftG1(vals, inv, 1)
+}
+
+func (fs *FFTSettings) fftG1(vals []bls.G1Point, inv bool, workers int) ([]bls.G1Point, error) {
 	n := uint64(len(vals))
 	if n > fs.MaxWidth {
-		return nil, fmt.Errorf("got %d values but only have %d roots of unity", n, fs.MaxWidth)
+		return nil, fmt.Errorf("%w: got %d values but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
 	}
 	if !bls.IsPowerOfTwo(n) {
-		return nil, fmt.Errorf("got %d values but not a power of two", n)
+		return nil, fmt.Errorf("%w: got %d values", ErrNotPowerOfTwo, n)
 	}
 	// We make a copy so we can mutate it during the work.
 	valsCopy := make([]bls.G1Point, n, n)
@@ -76,19 +80,21 @@ func (fs *FFTSettings) FFTG1(vals []bls.G1Point, inv bool) ([]bls.G1Point, error
 		stride := fs.MaxWidth / n
 
 		out := make([]bls.G1Point, n, n)
-		fs._fftG1(valsCopy, 0, 1, rootz, stride, out)
-		var tmp bls.G1Point
-		for i := 0; i < len(out); i++ {
-			bls.MulG1(&tmp, &out[i], &invLen)
-			bls.CopyG1(&out[i], &tmp)
-		}
+		fs._fftG1Parallel(valsCopy, 0, 1, rootz, stride, out, workers)
+		parallelRange(n, workers, parallelFFTG1Threshold, func(start, end uint64) {
+			var tmp bls.G1Point
+			for i := start; i < end; i++ {
+				bls.MulG1(&tmp, &out[i], &invLen)
+				bls.CopyG1(&out[i], &tmp)
+			}
+		})
 		return out, nil
 	} else {
 		out := make([]bls.G1Point, n, n)
 		rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
 		stride := fs.MaxWidth / n
 		// Regular FFT
-		fs._fftG1(valsCopy, 0, 1, rootz, stride, out)
+		fs._fftG1Parallel(valsCopy, 0, 1, rootz, stride, out, workers)
 		return out, nil
 	}
 }
@@ -105,3 +111,32 @@ func reverseBitOrderG1(values []bls.G1Point) {
 		bls.CopyG1(&values[j], &tmp)
 	})
 }
+
+// CosetFFTG1 is the G1 equivalent of CosetFFT: out[i] = sum_j vals[j] * (shift * w^i)^j
+func (fs *FFTSettings) CosetFFTG1(vals []bls.G1Point, coset *CosetPowers) ([]bls.G1Point, error) {
+	if len(vals) > len(coset.Powers) {
+		return nil, fmt.Errorf("%w: got %d values but only have %d coset powers", ErrDomainTooSmall, len(vals), len(coset.Powers))
+	}
+	shifted := make([]bls.G1Point, len(vals), len(vals))
+	for i := range vals {
+		bls.MulG1(&shifted[i], &vals[i], &coset.Powers[i])
+	}
+	return fs.FFTG1(shifted, false)
+}
+
+// CosetIFFTG1 is the G1 equivalent of CosetIFFT, the inverse of CosetFFTG1.
+func (fs *FFTSettings) CosetIFFTG1(vals []bls.G1Point, coset *CosetPowers) ([]bls.G1Point, error) {
+	if len(vals) > len(coset.InvPowers) {
+		return nil, fmt.Errorf("%w: got %d values but only have %d coset powers", ErrDomainTooSmall, len(vals), len(coset.InvPowers))
+	}
+	out, err := fs.FFTG1(vals, true)
+	if err != nil {
+		return nil, err
+	}
+	var tmp bls.G1Point
+	for i := range out {
+		bls.MulG1(&tmp, &out[i], &coset.InvPowers[i])
+		bls.CopyG1(&out[i], &tmp)
+	}
+	return out, nil
+}
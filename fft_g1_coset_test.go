@@ -0,0 +1,47 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestCosetFFTG1(t *testing.T) {
+	fs := NewFFTSettings(4)
+	var shift bls.Fr
+	bls.AsFr(&shift, 11)
+	coset := NewCosetPowers(&shift, fs.MaxWidth)
+	scalars := make([]bls.Fr, 16, 16)
+	data := make([]bls.G1Point, 16, 16)
+	for i := range data {
+		bls.AsFr(&scalars[i], uint64(i*i+7))
+		bls.MulG1(&data[i], &bls.GenG1, &scalars[i])
+	}
+	expectedScalars, err := fs.CosetFFT(scalars, coset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fs.CosetFFTG1(data, coset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range got {
+		var expected bls.G1Point
+		bls.MulG1(&expected, &bls.GenG1, &expectedScalars[i])
+		if !bls.EqualG1(&got[i], &expected) {
+			t.Fatalf("difference at %d: got %s, expected %s", i, bls.StrG1(&got[i]), bls.StrG1(&expected))
+		}
+	}
+	back, err := fs.CosetIFFTG1(got, coset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range back {
+		if !bls.EqualG1(&back[i], &data[i]) {
+			t.Fatalf("roundtrip difference at %d: got %s, expected %s", i, bls.StrG1(&back[i]), bls.StrG1(&data[i]))
+		}
+	}
+}
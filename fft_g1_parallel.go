@@ -0,0 +1,50 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// ParallelFFTG1 is the same as FFTG1, but splits the work across goroutines, with the same results.
+// The number of workers is limited to the given count, or to runtime.GOMAXPROCS if it is not positive.
+func (fs *FFTSettings) ParallelFFTG1(vals []bls.G1Point, inv bool, workers int) ([]bls.G1Point, error) {
+	return fs.fftG1(vals, inv, fftWorkers(workers))
+}
+
+// _fftG1Parallel runs the two halves of the recursion, and then the butterflies, across the workers.
+func (fs *FFTSettings) _fftG1Parallel(vals []bls.G1Point, valsOffset uint64, valsStride uint64, rootsOfUnity []bls.Fr, rootsOfUnityStride uint64, out []bls.G1Point, workers int) {
+	if workers <= 1 || len(out) < parallelFFTG1Threshold {
+		fs._fftG1(vals, valsOffset, valsStride, rootsOfUnity, rootsOfUnityStride, out)
+		return
+	}
+
+	half := uint64(len(out)) >> 1
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		// L will be the left half of out
+		fs._fftG1Parallel(vals, valsOffset, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[:half], workers/2)
+		wg.Done()
+	}()
+	// R will be the right half of out
+	fs._fftG1Parallel(vals, valsOffset+valsStride, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[half:], workers-workers/2)
+	wg.Wait()
+
+	parallelRange(half, workers, parallelFFTG1Threshold/2, func(start, end uint64) {
+		var yTimesRoot bls.G1Point
+		var x, y bls.G1Point
+		for i := start; i < end; i++ {
+			// temporary copies, so that writing to output doesn't conflict with input
+			bls.CopyG1(&x, &out[i])
+			bls.CopyG1(&y, &out[i+half])
+			root := &rootsOfUnity[i*rootsOfUnityStride]
+			bls.MulG1(&yTimesRoot, &y, root)
+			bls.AddG1(&out[i], &x, &yTimesRoot)
+			bls.SubG1(&out[i+half], &x, &yTimesRoot)
+		}
+	})
+}
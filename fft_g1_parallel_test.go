@@ -0,0 +1,43 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestParallelFFTG1(t *testing.T) {
+	fs := NewFFTSettings(6)
+	for _, scale := range []uint8{0, 2, 4, 5, 6} {
+		n := uint64(1) << scale
+		data := make([]bls.G1Point, n, n)
+		for i := uint64(0); i < n; i++ {
+			var x bls.Fr
+			bls.AsFr(&x, i*i+7)
+			bls.MulG1(&data[i], &bls.GenG1, &x)
+		}
+		for _, inv := range []bool{false, true} {
+			expected, err := fs.FFTG1(data, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, workers := range []int{0, 2, 3, 8} {
+				t.Run(fmt.Sprintf("scale_%d_inv_%v_workers_%d", scale, inv, workers), func(t *testing.T) {
+					got, err := fs.ParallelFFTG1(data, inv, workers)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for i := range expected {
+						if !bls.EqualG1(&got[i], &expected[i]) {
+							t.Fatalf("difference at %d: got %s, expected %s", i, bls.StrG1(&got[i]), bls.StrG1(&expected[i]))
+						}
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func (fs *FFTSettings) simpleFTG2(vals []bls.G2Point, valsOffset uint64, valsStride uint64, rootsOfUnity []bls.Fr, rootsOfUnityStride uint64, out []bls.G2Point) {
+	l := uint64(len(out))
+	var v bls.G2Point
+	var tmp bls.G2Point
+	var last bls.G2Point
+	for i := uint64(0); i < l; i++ {
+		jv := &vals[valsOffset]
+		r := &rootsOfUnity[0]
+		bls.MulG2(&v, jv, r)
+		bls.CopyG2(&last, &v)
+
+		for j := uint64(1); j < l; j++ {
+			jv := &vals[valsOffset+j*valsStride]
+			r := &rootsOfUnity[((i*j)%l)*rootsOfUnityStride]
+			bls.MulG2(&v, jv, r)
+			bls.CopyG2(&tmp, &last)
+			bls.AddG2(&last, &tmp, &v)
+		}
+		bls.CopyG2(&out[i], &last)
+	}
+}
+
+func (fs *FFTSettings) _fftG2(vals []bls.G2Point, valsOffset uint64, valsStride uint64, rootsOfUnity []bls.Fr, rootsOfUnityStride uint64, out []bls.G2Point) {
+	if len(out) <= 4 { // if the value count is small, run the unoptimized version instead.
+		fs.simpleFTG2(vals, valsOffset, valsStride, rootsOfUnity, rootsOfUnityStride, out)
+		return
+	}
+
+	half := uint64(len(out)) >> 1
+	// L will be the left half of out
+	fs._fftG2(vals, valsOffset, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[:half])
+	// R will be the right half of out
+	fs._fftG2(vals, valsOffset+valsStride, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[half:]) // just take even again
+
+	var yTimesRoot bls.G2Point
+	var x, y bls.G2Point
+	for i := uint64(0); i < half; i++ {
+		// temporary copies, so that writing to output doesn't conflict with input
+		bls.CopyG2(&x, &out[i])
+		bls.CopyG2(&y, &out[i+half])
+		root := &rootsOfUnity[i*rootsOfUnityStride]
+		bls.MulG2(&yTimesRoot, &y, root)
+		bls.AddG2(&out[i], &x, &yTimesRoot)
+		bls.SubG2(&out[i+half], &x, &yTimesRoot)
+	}
+}
+
+// FFTG2 is the G2 equivalent of FFTG1, e.g. to convert G2 setup points between monomial and Lagrange form.
+func (fs *FFTSettings) FFTG2(vals []bls.G2Point, inv bool) ([]bls.G2Point, error) {
+	n := uint64(len(vals))
+	if n > fs.MaxWidth {
+		return nil, fmt.Errorf("%w: got %d values but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n) {
+		return nil, fmt.Errorf("%w: got %d values", ErrNotPowerOfTwo, n)
+	}
+	// We make a copy so we can mutate it during the work.
+	valsCopy := make([]bls.G2Point, n, n)
+	for i := 0; i < len(vals); i++ {
+		bls.CopyG2(&valsCopy[i], &vals[i])
+	}
+	out := make([]bls.G2Point, n, n)
+	stride := fs.MaxWidth / n
+	if inv {
+		var invLen bls.Fr
+		bls.AsFr(&invLen, n)
+		bls.InvModFr(&invLen, &invLen)
+		rootz := fs.ReverseRootsOfUnity[:fs.MaxWidth]
+
+		fs._fftG2(valsCopy, 0, 1, rootz, stride, out)
+		var tmp bls.G2Point
+		for i := range out {
+			bls.MulG2(&tmp, &out[i], &invLen)
+			bls.CopyG2(&out[i], &tmp)
+		}
+		return out, nil
+	} else {
+		rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
+		// Regular FFT
+		fs._fftG2(valsCopy, 0, 1, rootz, stride, out)
+		return out, nil
+	}
+}
+
+// rearrange G2 elements in reverse bit order. Supports 2**31 max element count.
+func reverseBitOrderG2(values []bls.G2Point) {
+	if len(values) > (1 << 31) {
+		panic("list too large")
+	}
+	var tmp bls.G2Point
+	reverseBitOrder(uint32(len(values)), func(i, j uint32) {
+		bls.CopyG2(&tmp, &values[i])
+		bls.CopyG2(&values[i], &values[j])
+		bls.CopyG2(&values[j], &tmp)
+	})
+}
@@ -0,0 +1,36 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"github.com/protolambda/go-kzg/bls"
+	"testing"
+)
+
+func benchFFTG2(scale uint8, b *testing.B) {
+	fs := NewFFTSettings(scale)
+	data := make([]bls.G2Point, fs.MaxWidth, fs.MaxWidth)
+	for i := uint64(0); i < fs.MaxWidth; i++ {
+		bls.MulG2(&data[i], &bls.GenG2, bls.RandomFr())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out, err := fs.FFTG2(data, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(out) != len(data) {
+			panic("output len doesn't match input")
+		}
+	}
+}
+
+func BenchmarkFFTSettings_FFTG2(b *testing.B) {
+	for scale := uint8(4); scale < 12; scale++ {
+		b.Run(fmt.Sprintf("scale_%d", scale), func(b *testing.B) {
+			benchFFTG2(scale, b)
+		})
+	}
+}
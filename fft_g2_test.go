@@ -0,0 +1,75 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestFFTG2(t *testing.T) {
+	fs := NewFFTSettings(4)
+	for _, scale := range []uint8{0, 1, 3, 4} {
+		n := uint64(1) << scale
+		scalars := make([]bls.Fr, n, n)
+		data := make([]bls.G2Point, n, n)
+		for i := uint64(0); i < n; i++ {
+			bls.AsFr(&scalars[i], i*i+7)
+			bls.MulG2(&data[i], &bls.GenG2, &scalars[i])
+		}
+		for _, inv := range []bool{false, true} {
+			t.Run(fmt.Sprintf("scale_%d_inv_%v", scale, inv), func(t *testing.T) {
+				expectedScalars, err := fs.FFT(scalars, inv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := fs.FFTG2(data, inv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := range got {
+					var expected bls.G2Point
+					bls.MulG2(&expected, &bls.GenG2, &expectedScalars[i])
+					if !bls.EqualG2(&got[i], &expected) {
+						t.Fatalf("difference at %d: got %s, expected %s", i, bls.StrG2(&got[i]), bls.StrG2(&expected))
+					}
+				}
+				back, err := fs.FFTG2(got, !inv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := range back {
+					if !bls.EqualG2(&back[i], &data[i]) {
+						t.Fatalf("roundtrip difference at %d", i)
+					}
+				}
+			})
+		}
+	}
+	if _, err := fs.FFTG2(make([]bls.G2Point, 3), false); err == nil {
+		t.Fatal("expected an error for a length that is not a power of two")
+	}
+}
+
+func TestReverseBitOrderG2(t *testing.T) {
+	n := uint64(8)
+	data := make([]bls.G2Point, n, n)
+	for i := uint64(0); i < n; i++ {
+		var x bls.Fr
+		bls.AsFr(&x, i+1)
+		bls.MulG2(&data[i], &bls.GenG2, &x)
+	}
+	reversed := make([]bls.G2Point, n, n)
+	for i := range data {
+		bls.CopyG2(&reversed[i], &data[i])
+	}
+	reverseBitOrderG2(reversed)
+	for i := uint64(0); i < n; i++ {
+		if !bls.EqualG2(&reversed[i], &data[reverseBitsLimited(uint32(n), uint32(i))]) {
+			t.Fatalf("bad reversal at %d", i)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package kzg
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Sub-FFTs smaller than the threshold are not split across goroutines, the overhead outweighs the work.
+const (
+	parallelFFTThreshold   = 1 << 10
+	parallelFFTG1Threshold = 1 << 4
+)
+
+// ParallelFFT is the same as FFT, but splits the work across goroutines, with the same results.
+// The number of workers is limited to the given count, or to runtime.GOMAXPROCS if it is not positive.
+func (fs *FFTSettings) ParallelFFT(vals []bls.Fr, inv bool, workers int) ([]bls.Fr, error) {
+	return fs.fft(vals, inv, fftWorkers(workers))
+}
+
+// ParallelInplaceFFT is the same as InplaceFFT, but splits the work across goroutines, with the same results.
+// The number of workers is limited to the given count, or to runtime.GOMAXPROCS if it is not positive.
+func (fs *FFTSettings) ParallelInplaceFFT(vals []bls.Fr, out []bls.Fr, inv bool, workers int) error {
+	return fs.inplaceFFT(vals, out, inv, fftWorkers(workers))
+}
+
+func fftWorkers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// _fftParallel runs the two halves of the recursion, and then the butterflies, across the workers.
+func (fs *FFTSettings) _fftParallel(vals []bls.Fr, valsOffset uint64, valsStride uint64, rootsOfUnity []bls.Fr, rootsOfUnityStride uint64, out []bls.Fr, workers int) {
+	if workers <= 1 || len(out) < parallelFFTThreshold {
+		fs._fft(vals, valsOffset, valsStride, rootsOfUnity, rootsOfUnityStride, out)
+		return
+	}
+
+	half := uint64(len(out)) >> 1
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		// L will be the left half of out
+		fs._fftParallel(vals, valsOffset, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[:half], workers/2)
+		wg.Done()
+	}()
+	// R will be the right half of out
+	fs._fftParallel(vals, valsOffset+valsStride, valsStride<<1, rootsOfUnity, rootsOfUnityStride<<1, out[half:], workers-workers/2)
+	wg.Wait()
+
+	parallelRange(half, workers, parallelFFTThreshold/2, func(start, end uint64) {
+		var yTimesRoot bls.Fr
+		var x, y bls.Fr
+		for i := start; i < end; i++ {
+			// temporary copies, so that writing to output doesn't conflict with input
+			bls.CopyFr(&x, &out[i])
+			bls.CopyFr(&y, &out[i+half])
+			root := &rootsOfUnity[i*rootsOfUnityStride]
+			bls.MulModFr(&yTimesRoot, &y, root)
+			bls.AddModFr(&out[i], &x, &yTimesRoot)
+			bls.SubModFr(&out[i+half], &x, &yTimesRoot)
+		}
+	})
+}
+
+// parallelRange splits [0, n) into at most workers ranges of at least minSize, and processes them concurrently.
+func parallelRange(n uint64, workers int, minSize uint64, fn func(start, end uint64)) {
+	if minSize == 0 {
+		minSize = 1
+	}
+	chunks := uint64(workers)
+	if max := n / minSize; chunks > max {
+		chunks = max
+	}
+	if chunks <= 1 {
+		fn(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(int(chunks))
+	for c := uint64(0); c < chunks; c++ {
+		start, end := n*c/chunks, n*(c+1)/chunks
+		go func() {
+			fn(start, end)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,48 @@
+package kzg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestParallelFFT(t *testing.T) {
+	fs := NewFFTSettings(12)
+	for _, scale := range []uint8{0, 1, 4, 10, 11, 12} {
+		n := uint64(1) << scale
+		data := make([]bls.Fr, n, n)
+		for i := uint64(0); i < n; i++ {
+			bls.AsFr(&data[i], i*i+7)
+		}
+		for _, inv := range []bool{false, true} {
+			expected, err := fs.FFT(data, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, workers := range []int{0, 1, 2, 3, 8} {
+				t.Run(fmt.Sprintf("scale_%d_inv_%v_workers_%d", scale, inv, workers), func(t *testing.T) {
+					got, err := fs.ParallelFFT(data, inv, workers)
+					if err != nil {
+						t.Fatal(err)
+					}
+					out := make([]bls.Fr, n, n)
+					if err := fs.ParallelInplaceFFT(append([]bls.Fr{}, data...), out, inv, workers); err != nil {
+						t.Fatal(err)
+					}
+					for i := range expected {
+						if !bls.EqualFr(&got[i], &expected[i]) {
+							t.Fatalf("difference at %d: got %s, expected %s", i, bls.FrStr(&got[i]), bls.FrStr(&expected[i]))
+						}
+						if !bls.EqualFr(&out[i], &expected[i]) {
+							t.Fatalf("in-place difference at %d: got %s, expected %s", i, bls.FrStr(&out[i]), bls.FrStr(&expected[i]))
+						}
+					}
+				})
+			}
+		}
+	}
+	if _, err := fs.ParallelFFT(make([]bls.Fr, 8192), false, 4); err == nil {
+		t.Fatal("expected error for input larger than the domain")
+	}
+}
@@ -23,48 +23,92 @@ import (
 // 	   proof[i]: w^(i*l + 0), w^(i*l + 1), ... w^(i*l + l - 1)
 // 	   ...
 func (ks *FK20MultiSettings) FK20Multi(polynomial []bls.Fr) []bls.G1Point {
+	out, err := ks.TryFK20Multi(polynomial)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryFK20Multi is the error-returning variant of FK20Multi
+func (ks *FK20MultiSettings) TryFK20Multi(polynomial []bls.Fr) ([]bls.G1Point, error) {
 	n := uint64(len(polynomial))
 	n2 := n * 2
 	if ks.MaxWidth < n2 {
-		panic(fmt.Errorf("KZGSettings are set to MaxWidth %d but got half polynomial of length %d",
-			ks.MaxWidth, n))
+		return nil, fmt.Errorf("%w: KZGSettings are set to MaxWidth %d but got half polynomial of length %d",
+			ErrDomainTooSmall, ks.MaxWidth, n)
+	}
+	if err := ks.checkToeplitzInput(n); err != nil {
+		return nil, err
 	}
 
-	hExtFFT := make([]bls.G1Point, n2, n2)
-	for i := uint64(0); i < n2; i++ {
+	k := n / ks.chunkLen
+	k2 := k * 2
+	hExtFFT := make([]bls.G1Point, k2, k2)
+	for i := uint64(0); i < k2; i++ {
 		bls.CopyG1(&hExtFFT[i], &bls.ZeroG1)
 	}
 
 	var tmp bls.G1Point
 	for i := uint64(0); i < ks.chunkLen; i++ {
 		toeplitzCoeffs := ks.toeplitzCoeffsStepStrided(polynomial, i, ks.chunkLen)
-		hExtFFTFile := ks.ToeplitzPart2(toeplitzCoeffs, ks.xExtFFTFiles[i])
-		for j := uint64(0); j < n2; j++ {
+		hExtFFTFile, err := ks.TryToeplitzPart2(toeplitzCoeffs, ks.xExtFFTFiles[i])
+		if err != nil {
+			return nil, err
+		}
+		for j := uint64(0); j < k2; j++ {
 			bls.AddG1(&tmp, &hExtFFT[j], &hExtFFTFile[j])
 			bls.CopyG1(&hExtFFT[j], &tmp)
 		}
 	}
-	h := ks.ToeplitzPart3(hExtFFT)
-
-	out, err := ks.FFTG1(h, false)
+	h, err := ks.TryToeplitzPart3(hExtFFT)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return out
+
+	return ks.FFTG1(h, false)
+}
+
+// checks that a polynomial of n coefficients fits the precomputed toeplitz part 1 files
+func (ks *FK20MultiSettings) checkToeplitzInput(n uint64) error {
+	if !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("%w: expected polynomial length to be power of two, got %d", ErrNotPowerOfTwo, n)
+	}
+	if n < 2*ks.chunkLen {
+		return fmt.Errorf("%w: expected polynomial of at least 2 chunks of %d, got %d coefficients",
+			ErrSizeTooSmall, ks.chunkLen, n)
+	}
+	if k2 := (n / ks.chunkLen) * 2; k2 != uint64(len(ks.xExtFFTFiles[0])) {
+		return fmt.Errorf("%w: expected polynomial of %d coefficients to match FK20-multi settings, got %d",
+			ErrLengthMismatch, uint64(len(ks.xExtFFTFiles[0]))/2*ks.chunkLen, n)
+	}
+	return nil
 }
 
 // FK20 multi-proof method, optimized for dava availability where the top half of polynomial
 // coefficients == 0
 func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1Point {
+	out, err := ks.TryFK20MultiDAOptimized(polynomial)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryFK20MultiDAOptimized is the error-returning variant of FK20MultiDAOptimized
+func (ks *FK20MultiSettings) TryFK20MultiDAOptimized(polynomial []bls.Fr) ([]bls.G1Point, error) {
 	n2 := uint64(len(polynomial))
 	if ks.MaxWidth < n2 {
-		panic(fmt.Errorf("KZGSettings are set to MaxWidth %d but got polynomial of length %d",
-			ks.MaxWidth, n2))
+		return nil, fmt.Errorf("%w: KZGSettings are set to MaxWidth %d but got polynomial of length %d",
+			ErrDomainTooSmall, ks.MaxWidth, n2)
 	}
 	n := n2 / 2
+	if err := ks.checkToeplitzInput(n); err != nil {
+		return nil, err
+	}
 	for i := n; i < n2; i++ {
 		if !bls.EqualZero(&polynomial[i]) {
-			panic("bad input, second half should be zeroed")
+			return nil, fmt.Errorf("%w: bad input, second half should be zeroed", ErrNonZeroPadding)
 		}
 	}
 
@@ -81,7 +125,10 @@ func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1P
 		toeplitzCoeffs := ks.toeplitzCoeffsStepStrided(reducedPoly, i, ks.chunkLen)
 		//debugFrs(fmt.Sprintf("toeplitz_coefficients %d:", i), toeplitzCoeffs)
 		//DebugG1s(fmt.Sprintf("xext_fft file %d:", i), ks.xExtFFTFiles[i])
-		hExtFFTFile := ks.ToeplitzPart2(toeplitzCoeffs, ks.xExtFFTFiles[i])
+		hExtFFTFile, err := ks.TryToeplitzPart2(toeplitzCoeffs, ks.xExtFFTFiles[i])
+		if err != nil {
+			return nil, err
+		}
 		//DebugG1s(fmt.Sprintf("hext_fft file %d:", i), hExtFFTFile)
 		for j := uint64(0); j < k2; j++ {
 			bls.AddG1(&tmp, &hExtFFT[j], &hExtFFTFile[j])
@@ -90,7 +137,10 @@ func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1P
 		//DebugG1s(fmt.Sprintf("hext_fft %d:", i), hExtFFT)
 	}
 	//DebugG1s("hext_fft final", hExtFFT)
-	h := ks.ToeplitzPart3(hExtFFT)
+	h, err := ks.TryToeplitzPart3(hExtFFT)
+	if err != nil {
+		return nil, err
+	}
 	//DebugG1s("h", h)
 
 	// TODO: maybe use a G1 version of the DAS extension FFT to perform the h -> output conversion?
@@ -101,22 +151,28 @@ func (ks *FK20MultiSettings) FK20MultiDAOptimized(polynomial []bls.Fr) []bls.G1P
 	for i := k; i < k2; i++ {
 		bls.CopyG1(&h[i], &bls.ZeroG1)
 	}
-	out, err := ks.FFTG1(h, false)
+	return ks.FFTG1(h, false)
+}
+
+// Computes all the KZG proofs for data availability checks. This involves sampling on the double domain
+// and reordering according to reverse bit order
+func (ks *FK20MultiSettings) DAUsingFK20Multi(polynomial []bls.Fr) []bls.G1Point {
+	out, err := ks.TryDAUsingFK20Multi(polynomial)
 	if err != nil {
 		panic(err)
 	}
 	return out
 }
 
-// Computes all the KZG proofs for data availability checks. This involves sampling on the double domain
-// and reordering according to reverse bit order
-func (ks *FK20MultiSettings) DAUsingFK20Multi(polynomial []bls.Fr) []bls.G1Point {
+// TryDAUsingFK20Multi is the error-returning variant of DAUsingFK20Multi
+func (ks *FK20MultiSettings) TryDAUsingFK20Multi(polynomial []bls.Fr) ([]bls.G1Point, error) {
 	n := uint64(len(polynomial))
 	if n > ks.MaxWidth/2 {
-		panic("expected poly contents not bigger than half the size of the FK20-multi settings")
+		return nil, fmt.Errorf("%w: expected poly contents (%d) not bigger than half the size of the FK20-multi settings (%d)",
+			ErrDomainTooSmall, n, ks.MaxWidth)
 	}
 	if !bls.IsPowerOfTwo(n) {
-		panic("expected poly length to be power of two")
+		return nil, fmt.Errorf("%w: expected poly length to be power of two, got %d", ErrNotPowerOfTwo, n)
 	}
 	n2 := n * 2
 	extendedPolynomial := make([]bls.Fr, n2, n2)
@@ -126,8 +182,11 @@ func (ks *FK20MultiSettings) DAUsingFK20Multi(polynomial []bls.Fr) []bls.G1Point
 	for i := n; i < n2; i++ {
 		bls.CopyFr(&extendedPolynomial[i], &bls.ZERO)
 	}
-	allProofs := ks.FK20MultiDAOptimized(extendedPolynomial)
+	allProofs, err := ks.TryFK20MultiDAOptimized(extendedPolynomial)
+	if err != nil {
+		return nil, err
+	}
 	// change to reverse bit order.
 	reverseBitOrderG1(allProofs)
-	return allProofs
+	return allProofs, nil
 }
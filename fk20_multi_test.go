@@ -4,6 +4,7 @@
 package kzg
 
 import (
+	"errors"
 	"github.com/protolambda/go-kzg/bls"
 	"testing"
 )
@@ -89,3 +90,68 @@ func TestKZGSettings_DAUsingFK20Multi(t *testing.T) {
 		t.Logf("Data availability check %d passed", pos)
 	}
 }
+
+func TestKZGSettings_TryDAUsingFK20Multi(t *testing.T) {
+	fs := NewFFTSettings(6)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 64)
+	ks := NewKZGSettings(fs, s1, s2)
+	fk := NewFK20MultiSettings(ks, 64, 4)
+
+	if _, err := fk.TryDAUsingFK20Multi(make([]bls.Fr, 64)); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+	if _, err := fk.TryDAUsingFK20Multi(make([]bls.Fr, 24)); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, err := fk.TryDAUsingFK20Multi(make([]bls.Fr, 16)); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch error, got: %v", err)
+	}
+	nonZeroPadded := make([]bls.Fr, 64)
+	bls.CopyFr(&nonZeroPadded[40], &bls.ONE)
+	if _, err := fk.TryFK20MultiDAOptimized(nonZeroPadded); !errors.Is(err, ErrNonZeroPadding) {
+		t.Fatalf("expected non-zero padding error, got: %v", err)
+	}
+	proofs, err := fk.TryDAUsingFK20Multi(make([]bls.Fr, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != 16 {
+		t.Fatalf("expected 16 proofs, got %d", len(proofs))
+	}
+}
+
+func TestKZGSettings_FK20Multi(t *testing.T) {
+	fs := NewFFTSettings(6)
+	chunkLen := uint64(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 64)
+	ks := NewKZGSettings(fs, s1, s2)
+	fk := NewFK20MultiSettings(ks, 64, chunkLen)
+
+	polynomial := make([]bls.Fr, 32, 32)
+	for i := range polynomial {
+		bls.AsFr(&polynomial[i], uint64(i*i+3))
+	}
+	commitment := ks.CommitToPoly(polynomial)
+	proofs, err := fk.TryFK20Multi(polynomial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(proofs)) != 32/chunkLen {
+		t.Fatalf("expected %d proofs, got %d", 32/chunkLen, len(proofs))
+	}
+	// proof i covers the coset of w^i, with w the 32-th root of unity
+	stride := fk.MaxWidth / 32
+	cosetStride := fk.MaxWidth / chunkLen
+	for i := uint64(0); i < uint64(len(proofs)); i++ {
+		x := &fk.ExpandedRootsOfUnity[i*stride]
+		ys := make([]bls.Fr, chunkLen, chunkLen)
+		for j := uint64(0); j < chunkLen; j++ {
+			var z bls.Fr
+			bls.MulModFr(&z, x, &fk.ExpandedRootsOfUnity[j*cosetStride])
+			bls.EvalPolyAt(&ys[j], polynomial, &z)
+		}
+		if !ks.CheckProofMulti(commitment, &proofs[i], x, ys) {
+			t.Fatalf("could not verify proof %d", i)
+		}
+	}
+}
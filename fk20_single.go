@@ -37,7 +37,7 @@ import (
 
 // Performs the first part of the Toeplitz matrix multiplication algorithm, which is a Fourier
 // transform of the vector x extended
-func (ks *KZGSettings) toeplitzPart1(x []bls.G1Point) []bls.G1Point {
+func (ks *KZGSettings) toeplitzPart1(x []bls.G1Point) ([]bls.G1Point, error) {
 	n := uint64(len(x))
 	n2 := n * 2
 	// Extend x with zeros (neutral element of G1)
@@ -50,19 +50,29 @@ func (ks *KZGSettings) toeplitzPart1(x []bls.G1Point) []bls.G1Point {
 	}
 	xExtFFT, err := ks.FFTG1(xExt, false)
 	if err != nil {
-		panic(fmt.Errorf("FFT G1 failed in toeplitz part 1: %v", err))
+		return nil, fmt.Errorf("FFT G1 failed in toeplitz part 1: %w", err)
 	}
-	return xExtFFT
+	return xExtFFT, nil
 }
 
 // Performs the second part of the Toeplitz matrix multiplication algorithm
 func (ks *KZGSettings) ToeplitzPart2(toeplitzCoeffs []bls.Fr, xExtFFT []bls.G1Point) (hExtFFT []bls.G1Point) {
+	hExtFFT, err := ks.TryToeplitzPart2(toeplitzCoeffs, xExtFFT)
+	if err != nil {
+		panic(err)
+	}
+	return hExtFFT
+}
+
+// TryToeplitzPart2 is the error-returning variant of ToeplitzPart2
+func (ks *KZGSettings) TryToeplitzPart2(toeplitzCoeffs []bls.Fr, xExtFFT []bls.G1Point) (hExtFFT []bls.G1Point, err error) {
 	if uint64(len(toeplitzCoeffs)) != uint64(len(xExtFFT)) {
-		panic("expected toeplitz coeffs to match xExtFFT length")
+		return nil, fmt.Errorf("%w: expected toeplitz coeffs (%d) to match xExtFFT length (%d)",
+			ErrLengthMismatch, len(toeplitzCoeffs), len(xExtFFT))
 	}
 	toeplitzCoeffsFFT, err := ks.FFT(toeplitzCoeffs, false)
 	if err != nil {
-		panic(fmt.Errorf("FFT failed in toeplitz part 2: %v", err))
+		return nil, fmt.Errorf("FFT failed in toeplitz part 2: %w", err)
 	}
 	//debugFrs("focus toeplitzCoeffsFFT", toeplitzCoeffsFFT)
 	//DebugG1s("xExtFFT", xExtFFT)
@@ -73,17 +83,26 @@ func (ks *KZGSettings) ToeplitzPart2(toeplitzCoeffs []bls.Fr, xExtFFT []bls.G1Po
 		bls.MulG1(&hExtFFT[i], &xExtFFT[i], &toeplitzCoeffsFFT[i])
 	}
 	//DebugG1s("hExtFFT", hExtFFT)
-	return hExtFFT
+	return hExtFFT, nil
 }
 
 // Transform back and return the first half of the vector
 func (ks *KZGSettings) ToeplitzPart3(hExtFFT []bls.G1Point) []bls.G1Point {
+	out, err := ks.TryToeplitzPart3(hExtFFT)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryToeplitzPart3 is the error-returning variant of ToeplitzPart3
+func (ks *KZGSettings) TryToeplitzPart3(hExtFFT []bls.G1Point) ([]bls.G1Point, error) {
 	out, err := ks.FFTG1(hExtFFT, true)
 	if err != nil {
-		panic(fmt.Errorf("toeplitz part 3 err: %v", err))
+		return nil, fmt.Errorf("toeplitz part 3 err: %w", err)
 	}
 	// Only the top half is the Toeplitz product, the rest is padding
-	return out[:len(out)/2]
+	return out[:len(out)/2], nil
 }
 
 func (ks *KZGSettings) toeplitzCoeffsStepStrided(polynomial []bls.Fr, offset uint64, stride uint64) []bls.Fr {
@@ -118,45 +137,89 @@ func (ks *KZGSettings) toeplitzCoeffsStep(polynomial []bls.Fr) []bls.Fr {
 	return toeplitzCoeffs
 }
 
+// checks that a polynomial of n coefficients fits the precomputed toeplitz part 1
+func (fk *FK20SingleSettings) checkToeplitzInput(n uint64) error {
+	if n < 2 {
+		return fmt.Errorf("%w: expected polynomial of at least 2 coefficients, got %d", ErrSizeTooSmall, n)
+	}
+	if n*2 != uint64(len(fk.xExtFFT)) {
+		return fmt.Errorf("%w: expected polynomial of %d coefficients to match FK20-single settings, got %d",
+			ErrLengthMismatch, len(fk.xExtFFT)/2, n)
+	}
+	return nil
+}
+
 // Compute all n (single) proofs according to FK20 method
 func (fk *FK20SingleSettings) FK20Single(polynomial []bls.Fr) []bls.G1Point {
-	toeplitzCoeffs := fk.toeplitzCoeffsStep(polynomial)
-	// Compute the vector h from the paper using a Toeplitz matrix multiplication
-	hExtFFT := fk.ToeplitzPart2(toeplitzCoeffs, fk.xExtFFT)
-	h := fk.ToeplitzPart3(hExtFFT)
-
-	// TODO: correct? It will pad up implicitly again, but
-	out, err := fk.FFTG1(h, false)
+	out, err := fk.TryFK20Single(polynomial)
 	if err != nil {
 		panic(err)
 	}
 	return out
 }
 
+// TryFK20Single is the error-returning variant of FK20Single
+func (fk *FK20SingleSettings) TryFK20Single(polynomial []bls.Fr) ([]bls.G1Point, error) {
+	if err := fk.checkToeplitzInput(uint64(len(polynomial))); err != nil {
+		return nil, err
+	}
+	toeplitzCoeffs := fk.toeplitzCoeffsStep(polynomial)
+	// Compute the vector h from the paper using a Toeplitz matrix multiplication
+	hExtFFT, err := fk.TryToeplitzPart2(toeplitzCoeffs, fk.xExtFFT)
+	if err != nil {
+		return nil, err
+	}
+	h, err := fk.TryToeplitzPart3(hExtFFT)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: correct? It will pad up implicitly again, but
+	return fk.FFTG1(h, false)
+}
+
 // Special version of the FK20 for the situation of data availability checks:
 // The upper half of the polynomial coefficients is always 0, so we do not need to extend to twice the size
 // for Toeplitz matrix multiplication
 func (fk *FK20SingleSettings) FK20SingleDAOptimized(polynomial []bls.Fr) []bls.G1Point {
+	out, err := fk.TryFK20SingleDAOptimized(polynomial)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryFK20SingleDAOptimized is the error-returning variant of FK20SingleDAOptimized
+func (fk *FK20SingleSettings) TryFK20SingleDAOptimized(polynomial []bls.Fr) ([]bls.G1Point, error) {
 	if uint64(len(polynomial)) > fk.MaxWidth {
-		panic(fmt.Errorf(
-			"expected input of length %d (incl half of zeroes) to not exceed precomputed settings length %d",
-			len(polynomial), fk.MaxWidth))
+		return nil, fmt.Errorf(
+			"%w: expected input of length %d (incl half of zeroes) to not exceed precomputed settings length %d",
+			ErrDomainTooSmall, len(polynomial), fk.MaxWidth)
 	}
 	n2 := uint64(len(polynomial))
 	if !bls.IsPowerOfTwo(n2) {
-		panic(fmt.Errorf("expected input length to be power of two, got %d", n2))
+		return nil, fmt.Errorf("%w: expected input length to be power of two, got %d", ErrNotPowerOfTwo, n2)
 	}
 	n := n2 / 2
+	if err := fk.checkToeplitzInput(n); err != nil {
+		return nil, err
+	}
 	for i := n; i < n2; i++ {
 		if !bls.EqualZero(&polynomial[i]) {
-			panic("bad input, second half should be zeroed")
+			return nil, fmt.Errorf("%w: bad input, second half should be zeroed", ErrNonZeroPadding)
 		}
 	}
 	reducedPoly := polynomial[:n]
 	toeplitzCoeffs := fk.toeplitzCoeffsStep(reducedPoly)
 	// Compute the vector h from the paper using a Toeplitz matrix multiplication
-	hExtFFT := fk.ToeplitzPart2(toeplitzCoeffs, fk.xExtFFT)
-	h := fk.ToeplitzPart3(hExtFFT)
+	hExtFFT, err := fk.TryToeplitzPart2(toeplitzCoeffs, fk.xExtFFT)
+	if err != nil {
+		return nil, err
+	}
+	h, err := fk.TryToeplitzPart3(hExtFFT)
+	if err != nil {
+		return nil, err
+	}
 
 	// Now redo the padding before final step.
 	// Instead of copying h into a new extended array, just reuse the old capacity.
@@ -164,22 +227,28 @@ func (fk *FK20SingleSettings) FK20SingleDAOptimized(polynomial []bls.Fr) []bls.G
 	for i := n; i < n2; i++ {
 		bls.CopyG1(&h[i], &bls.ZeroG1)
 	}
-	out, err := fk.FFTG1(h, false)
+	return fk.FFTG1(h, false)
+}
+
+// Computes all the KZG proofs for data availability checks. This involves sampling on the double domain
+// and reordering according to reverse bit order
+func (fk *FK20SingleSettings) DAUsingFK20(polynomial []bls.Fr) []bls.G1Point {
+	out, err := fk.TryDAUsingFK20(polynomial)
 	if err != nil {
 		panic(err)
 	}
 	return out
 }
 
-// Computes all the KZG proofs for data availability checks. This involves sampling on the double domain
-// and reordering according to reverse bit order
-func (fk *FK20SingleSettings) DAUsingFK20(polynomial []bls.Fr) []bls.G1Point {
+// TryDAUsingFK20 is the error-returning variant of DAUsingFK20
+func (fk *FK20SingleSettings) TryDAUsingFK20(polynomial []bls.Fr) ([]bls.G1Point, error) {
 	n := uint64(len(polynomial))
 	if n > fk.MaxWidth/2 {
-		panic("expected poly contents not bigger than half the size of the FK20-single settings")
+		return nil, fmt.Errorf("%w: expected poly contents (%d) not bigger than half the size of the FK20-single settings (%d)",
+			ErrDomainTooSmall, n, fk.MaxWidth)
 	}
 	if !bls.IsPowerOfTwo(n) {
-		panic("expected poly length to be power of two")
+		return nil, fmt.Errorf("%w: expected poly length to be power of two, got %d", ErrNotPowerOfTwo, n)
 	}
 	n2 := n * 2
 	extendedPolynomial := make([]bls.Fr, n2, n2)
@@ -189,8 +258,11 @@ func (fk *FK20SingleSettings) DAUsingFK20(polynomial []bls.Fr) []bls.G1Point {
 	for i := n; i < n2; i++ {
 		bls.CopyFr(&extendedPolynomial[i], &bls.ZERO)
 	}
-	allProofs := fk.FK20SingleDAOptimized(extendedPolynomial)
+	allProofs, err := fk.TryFK20SingleDAOptimized(extendedPolynomial)
+	if err != nil {
+		return nil, err
+	}
 	// change to reverse bit order.
 	reverseBitOrderG1(allProofs)
-	return allProofs
+	return allProofs, nil
 }
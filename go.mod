@@ -3,9 +3,18 @@ module github.com/protolambda/go-kzg
 go 1.18
 
 require (
+	github.com/consensys/gnark-crypto v0.12.1
 	github.com/herumi/bls-eth-go-binary v1.28.1
 	github.com/holiman/uint256 v1.2.1
 	github.com/kilic/bls12-381 v0.1.1-0.20220929213557-ca162e8a70f4
+	github.com/supranational/blst v0.3.16
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/sys v0.0.0-20220818161305-2296e01440c6 // indirect
+require (
+	github.com/bits-and-blooms/bitset v1.7.0 // indirect
+	github.com/consensys/bavard v0.1.13 // indirect
+	github.com/mmcloughlin/addchain v0.4.0 // indirect
+	golang.org/x/sys v0.9.0 // indirect
+	rsc.io/tmplfunc v0.0.3 // indirect
+)
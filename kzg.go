@@ -5,6 +5,7 @@ package kzg
 
 import (
 	"fmt"
+
 	"github.com/protolambda/go-kzg/bls"
 )
 
@@ -16,51 +17,109 @@ type KZGSettings struct {
 	SecretG1 []bls.G1Point
 	// [b.multiply(b.G2, pow(s, i, MODULUS)) for i in range(WIDTH+1)],
 	SecretG2 []bls.G2Point
+
+	// Lagrange form of SecretG1, derived on demand, per width. See LagrangeG1.
+	// Shared by copies of the settings, nil if the settings were not created with TryNewKZGSettings.
+	lagrange *lagrangeCache
 }
 
+// NewKZGSettings creates KZG settings, and panics if the setup does not match the FFT settings.
+// See TryNewKZGSettings for an error-returning variant.
 func NewKZGSettings(fs *FFTSettings, secretG1 []bls.G1Point, secretG2 []bls.G2Point) *KZGSettings {
+	ks, err := TryNewKZGSettings(fs, secretG1, secretG2)
+	if err != nil {
+		panic(err)
+	}
+	return ks
+}
+
+// TryNewKZGSettings creates KZG settings, or returns an error if the setup does not match the FFT settings.
+func TryNewKZGSettings(fs *FFTSettings, secretG1 []bls.G1Point, secretG2 []bls.G2Point) (*KZGSettings, error) {
 	if len(secretG1) != len(secretG2) {
-		panic("secret list lengths don't match")
+		return nil, fmt.Errorf("%w: secret list lengths don't match, G1: %d, G2: %d", ErrLengthMismatch, len(secretG1), len(secretG2))
 	}
 	if uint64(len(secretG1)) < fs.MaxWidth {
-		panic(fmt.Errorf("expected more values for secrets, MaxWidth: %d, got: %d", fs.MaxWidth, len(secretG1)))
+		return nil, fmt.Errorf("%w: expected more values for secrets, MaxWidth: %d, got: %d", ErrDomainTooSmall, fs.MaxWidth, len(secretG1))
 	}
 
 	ks := &KZGSettings{
 		FFTSettings: fs,
 		SecretG1:    secretG1,
 		SecretG2:    secretG2,
+		lagrange:    newLagrangeCache(),
 	}
 
-	return ks
+	return ks, nil
+}
+
+// checkExtendedSize checks that the extended size n2 of FK20 settings is a power of two, and fits the settings.
+func (ks *KZGSettings) checkExtendedSize(n2 uint64) error {
+	if n2 > ks.MaxWidth {
+		return fmt.Errorf("%w: extended size %d is larger than kzg settings supports (%d)", ErrDomainTooSmall, n2, ks.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(n2) {
+		return fmt.Errorf("%w: extended size %d", ErrNotPowerOfTwo, n2)
+	}
+	if n2 < 2 {
+		return fmt.Errorf("%w: extended size %d", ErrSizeTooSmall, n2)
+	}
+	return nil
+}
+
+// checkChunkLen checks that the chunk length of FK20 multi settings is a power of two, and fits the extended size n2.
+func checkChunkLen(n2 uint64, chunkLen uint64) error {
+	if chunkLen > n2/2 {
+		return fmt.Errorf("%w: chunk length %d is larger than half the extended size %d", ErrSizeTooLarge, chunkLen, n2)
+	}
+	if !bls.IsPowerOfTwo(chunkLen) {
+		return fmt.Errorf("%w: chunk length %d", ErrNotPowerOfTwo, chunkLen)
+	}
+	if chunkLen < 1 {
+		return fmt.Errorf("%w: chunk length %d", ErrSizeTooSmall, chunkLen)
+	}
+	return nil
 }
 
 type FK20SingleSettings struct {
 	*KZGSettings
 	xExtFFT []bls.G1Point
+
+	// commitments to (X^n - z^n)/(X - z) for each point z of the extended domain, derived on demand.
+	// See UpdateDAProofs. Shared by copies of the settings, nil if the settings were not created with TryNewFK20SingleSettings.
+	updateBases *updateBasesCache
 }
 
+// NewFK20SingleSettings creates FK20 single-proof settings, and panics on invalid sizes.
+// See TryNewFK20SingleSettings for an error-returning variant.
 func NewFK20SingleSettings(ks *KZGSettings, n2 uint64) *FK20SingleSettings {
-	if n2 > ks.MaxWidth {
-		panic("extended size is larger than kzg settings supports")
-	}
-	if !bls.IsPowerOfTwo(n2) {
-		panic("extended size is not a power of two")
+	fk, err := TryNewFK20SingleSettings(ks, n2)
+	if err != nil {
+		panic(err)
 	}
-	if n2 < 2 {
-		panic("extended size is too small")
+	return fk
+}
+
+// TryNewFK20SingleSettings creates FK20 single-proof settings, or returns an error on invalid sizes.
+func TryNewFK20SingleSettings(ks *KZGSettings, n2 uint64) (*FK20SingleSettings, error) {
+	if err := ks.checkExtendedSize(n2); err != nil {
+		return nil, err
 	}
 	n := n2 / 2
 	fk := &FK20SingleSettings{
 		KZGSettings: ks,
+		updateBases: &updateBasesCache{},
 	}
 	x := make([]bls.G1Point, n, n)
 	for i, j := uint64(0), n-2; i < n-1; i, j = i+1, j-1 {
 		bls.CopyG1(&x[i], &ks.SecretG1[j])
 	}
 	bls.CopyG1(&x[n-1], &bls.ZeroG1)
-	fk.xExtFFT = fk.toeplitzPart1(x)
-	return fk
+	xExtFFT, err := fk.toeplitzPart1(x)
+	if err != nil {
+		return nil, err
+	}
+	fk.xExtFFT = xExtFFT
+	return fk, nil
 }
 
 type FK20MultiSettings struct {
@@ -70,24 +129,23 @@ type FK20MultiSettings struct {
 	xExtFFTFiles [][]bls.G1Point
 }
 
+// NewFK20MultiSettings creates FK20 multi-proof settings, and panics on invalid sizes.
+// See TryNewFK20MultiSettings for an error-returning variant.
 func NewFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20MultiSettings {
-	if n2 > ks.MaxWidth {
-		panic("extended size is larger than kzg settings supports")
-	}
-	if !bls.IsPowerOfTwo(n2) {
-		panic("extended size is not a power of two")
+	fk, err := TryNewFK20MultiSettings(ks, n2, chunkLen)
+	if err != nil {
+		panic(err)
 	}
-	if n2 < 2 {
-		panic("extended size is too small")
-	}
-	if chunkLen > n2/2 {
-		panic("chunk length is too large")
-	}
-	if !bls.IsPowerOfTwo(chunkLen) {
-		panic("chunk length must be power of two")
+	return fk
+}
+
+// TryNewFK20MultiSettings creates FK20 multi-proof settings, or returns an error on invalid sizes.
+func TryNewFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) (*FK20MultiSettings, error) {
+	if err := ks.checkExtendedSize(n2); err != nil {
+		return nil, err
 	}
-	if chunkLen < 1 {
-		panic("chunk length is too small")
+	if err := checkChunkLen(n2, chunkLen); err != nil {
+		return nil, err
 	}
 	fk := &FK20MultiSettings{
 		KZGSettings:  ks,
@@ -100,7 +158,7 @@ func NewFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20Mult
 	//   xext_fft.append(toeplitz_part1(x))
 	n := n2 / 2
 	k := n / chunkLen
-	xExtFFTPrecompute := func(offset uint64) []bls.G1Point {
+	xExtFFTPrecompute := func(offset uint64) ([]bls.G1Point, error) {
 		x := make([]bls.G1Point, k, k)
 		start := n - chunkLen - 1 - offset
 		for i, j := uint64(0), start; i+1 < k; i, j = i+1, j-chunkLen {
@@ -110,7 +168,11 @@ func NewFK20MultiSettings(ks *KZGSettings, n2 uint64, chunkLen uint64) *FK20Mult
 		return ks.toeplitzPart1(x)
 	}
 	for i := uint64(0); i < chunkLen; i++ {
-		fk.xExtFFTFiles[i] = xExtFFTPrecompute(i)
+		xExtFFT, err := xExtFFTPrecompute(i)
+		if err != nil {
+			return nil, err
+		}
+		fk.xExtFFTFiles[i] = xExtFFT
 	}
-	return fk
+	return fk, nil
 }
@@ -0,0 +1,121 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Check a batch of proofs for KZG commitments for evaluations f_i(x_i) = y_i, with a single pairing check.
+//
+// Each individual check is
+//
+//	e([commitment_i - y_i], [1]) = e([proof_i], [s - x_i])
+//	   equivalent to
+//	e([commitment_i - y_i + x_i * proof_i], [1]) = e([proof_i], [s])
+//
+// and with random challenges r_i the checks are combined into
+//
+//	e(sum_i r_i * [commitment_i - y_i + x_i * proof_i], [1]) = e(sum_i r_i * [proof_i], [s])
+//
+// which costs two multi-scalar multiplications and two Miller loops for the whole batch.
+// An error is returned if the input lengths do not match.
+// If the batch fails, use InvalidProofsSingle to find which proofs are invalid.
+func (ks *KZGSettings) CheckProofSingleBatch(commitments []bls.G1Point, proofs []bls.G1Point, xs []bls.Fr, ys []bls.Fr) (bool, error) {
+	n := len(commitments)
+	if len(proofs) != n || len(xs) != n || len(ys) != n {
+		return false, fmt.Errorf("%w: got %d commitments, %d proofs, %d xs, %d ys",
+			ErrLengthMismatch, n, len(proofs), len(xs), len(ys))
+	}
+	if n == 0 {
+		return true, nil
+	}
+	if n == 1 {
+		return ks.CheckProofSingle(&commitments[0], &proofs[0], &xs[0], &ys[0]), nil
+	}
+
+	return CheckProofSingleBatchWithSetup(&ks.SecretG2[1], commitments, proofs, xs, ys)
+}
+
+// CheckProofSingleBatchWithSetup is CheckProofSingleBatch for a setup of which only sG2 = [s]_2 is known,
+// e.g. a setup without all the G2 points. Random challenges are used.
+func CheckProofSingleBatchWithSetup(sG2 *bls.G2Point, commitments []bls.G1Point, proofs []bls.G1Point, xs []bls.Fr, ys []bls.Fr) (bool, error) {
+	rs := make([]bls.Fr, len(commitments), len(commitments))
+	for i := range rs {
+		bls.CopyFr(&rs[i], bls.RandomFr())
+	}
+	return CheckProofSingleBatchWithChallenges(sG2, commitments, proofs, xs, ys, rs)
+}
+
+// CheckProofSingleBatchWithChallenges is CheckProofSingleBatch with the given challenges rs instead of random ones,
+// e.g. derived with Fiat-Shamir, and with sG2 = [s]_2 of the setup.
+// The challenges must be unpredictable to whoever created the proofs, for the batch check to be sound.
+// An error is returned if the input lengths do not match.
+func CheckProofSingleBatchWithChallenges(sG2 *bls.G2Point, commitments []bls.G1Point, proofs []bls.G1Point, xs []bls.Fr, ys []bls.Fr, rs []bls.Fr) (bool, error) {
+	n := len(commitments)
+	if len(proofs) != n || len(xs) != n || len(ys) != n || len(rs) != n {
+		return false, fmt.Errorf("%w: got %d commitments, %d proofs, %d xs, %d ys, %d challenges",
+			ErrLengthMismatch, n, len(proofs), len(xs), len(ys), len(rs))
+	}
+	if n == 0 {
+		return true, nil
+	}
+
+	// [commitment_0, ..., commitment_(n-1), proof_0, ..., proof_(n-1), G1]
+	points := make([]bls.G1Point, 2*n+1, 2*n+1)
+	// [r_0, ..., r_(n-1), r_0 * x_0, ..., r_(n-1) * x_(n-1), -sum_i r_i * y_i]
+	scalars := make([]bls.Fr, 2*n+1, 2*n+1)
+	var sumRY, tmp bls.Fr
+	for i := 0; i < n; i++ {
+		bls.CopyG1(&points[i], &commitments[i])
+		bls.CopyG1(&points[n+i], &proofs[i])
+		bls.CopyFr(&scalars[i], &rs[i])
+		bls.MulModFr(&scalars[n+i], &rs[i], &xs[i])
+		bls.MulModFr(&tmp, &rs[i], &ys[i])
+		bls.AddModFr(&sumRY, &sumRY, &tmp)
+	}
+	bls.CopyG1(&points[2*n], &bls.GenG1)
+	bls.SubModFr(&scalars[2*n], &bls.ZERO, &sumRY)
+
+	lhs := bls.LinCombG1(points, scalars)
+	rhs := bls.LinCombG1(proofs, rs)
+
+	return bls.PairingsVerify(lhs, &bls.GenG2, rhs, sG2), nil
+}
+
+// InvalidProofsSingle returns the indices of the proofs in the batch that do not verify, in ascending order.
+// The batch is recursively split in halves, so only the failing parts are checked in detail.
+// An error is returned if the input lengths do not match.
+func (ks *KZGSettings) InvalidProofsSingle(commitments []bls.G1Point, proofs []bls.G1Point, xs []bls.Fr, ys []bls.Fr) ([]int, error) {
+	return InvalidProofsSingleWithSetup(&ks.SecretG2[1], commitments, proofs, xs, ys)
+}
+
+// InvalidProofsSingleWithSetup is InvalidProofsSingle for a setup of which only sG2 = [s]_2 is known.
+func InvalidProofsSingleWithSetup(sG2 *bls.G2Point, commitments []bls.G1Point, proofs []bls.G1Point, xs []bls.Fr, ys []bls.Fr) ([]int, error) {
+	n := len(commitments)
+	if len(proofs) != n || len(xs) != n || len(ys) != n {
+		return nil, fmt.Errorf("%w: got %d commitments, %d proofs, %d xs, %d ys",
+			ErrLengthMismatch, n, len(proofs), len(xs), len(ys))
+	}
+	var invalid []int
+	var bisect func(start, end int)
+	bisect = func(start, end int) {
+		ok, _ := CheckProofSingleBatchWithSetup(sG2, commitments[start:end], proofs[start:end], xs[start:end], ys[start:end])
+		if ok {
+			return
+		}
+		if end-start == 1 {
+			invalid = append(invalid, start)
+			return
+		}
+		mid := start + (end-start)/2
+		bisect(start, mid)
+		bisect(mid, end)
+	}
+	if n > 0 {
+		bisect(0, n)
+	}
+	return invalid, nil
+}
@@ -0,0 +1,70 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"github.com/protolambda/go-kzg/bls"
+	"testing"
+)
+
+func batchTestClaims(ks *KZGSettings, count int) (commitments []bls.G1Point, proofs []bls.G1Point, xs []bls.Fr, ys []bls.Fr) {
+	commitments = make([]bls.G1Point, count, count)
+	proofs = make([]bls.G1Point, count, count)
+	xs = make([]bls.Fr, count, count)
+	ys = make([]bls.Fr, count, count)
+	for i := 0; i < count; i++ {
+		polynomial := testPoly(1, 2, 3, 4, 7, 7, 7, 7, 13, 13, 13, 13, 13, 13, 13, uint64(i))
+		bls.CopyG1(&commitments[i], ks.CommitToPoly(polynomial))
+		x := uint64(17 + i*3)
+		bls.CopyG1(&proofs[i], ks.ComputeProofSingle(polynomial, x))
+		bls.AsFr(&xs[i], x)
+		bls.EvalPolyAt(&ys[i], polynomial, &xs[i])
+	}
+	return
+}
+
+func TestKZGSettings_CheckProofSingleBatch(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+
+	commitments, proofs, xs, ys := batchTestClaims(ks, 10)
+	ok, err := ks.CheckProofSingleBatch(commitments, proofs, xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("could not verify batch of proofs")
+	}
+	invalid, err := ks.InvalidProofsSingle(commitments, proofs, xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invalid) != 0 {
+		t.Fatalf("expected no invalid proofs, got %v", invalid)
+	}
+
+	// corrupt a few claims
+	bls.AddModFr(&ys[3], &ys[3], &bls.ONE)
+	bls.CopyG1(&proofs[7], &proofs[8])
+	ok, err = ks.CheckProofSingleBatch(commitments, proofs, xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected batch with invalid proofs to fail")
+	}
+	invalid, err = ks.InvalidProofsSingle(commitments, proofs, xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invalid) != 2 || invalid[0] != 3 || invalid[1] != 7 {
+		t.Fatalf("expected proofs 3 and 7 to be invalid, got %v", invalid)
+	}
+
+	if _, err := ks.CheckProofSingleBatch(commitments, proofs[:9], xs, ys); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch error, got: %v", err)
+	}
+}
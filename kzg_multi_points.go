@@ -0,0 +1,108 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// ComputeProofMultiPoints computes a KZG proof for the polynomial in coefficient form at an arbitrary set
+// of distinct points xs, unlike ComputeProofMulti the points do not have to form a coset of roots of unity.
+// The proof is a commitment to the quotient of the polynomial by the vanishing polynomial Z_S(X) of the points.
+func (ks *KZGSettings) ComputeProofMultiPoints(poly []bls.Fr, xs []bls.Fr) *bls.G1Point {
+	proof, err := ks.TryComputeProofMultiPoints(poly, xs)
+	if err != nil {
+		panic(err)
+	}
+	return proof
+}
+
+// TryComputeProofMultiPoints is the error-returning variant of ComputeProofMultiPoints
+func (ks *KZGSettings) TryComputeProofMultiPoints(poly []bls.Fr, xs []bls.Fr) (*bls.G1Point, error) {
+	if len(xs) == 0 {
+		return nil, fmt.Errorf("%w: cannot open at 0 points", ErrSizeTooSmall)
+	}
+	if err := checkDistinctPoints(xs); err != nil {
+		return nil, err
+	}
+	if len(poly) <= len(xs) {
+		// the interpolation polynomial is the polynomial itself, the quotient is zero
+		var out bls.G1Point
+		bls.CopyG1(&out, &bls.ZeroG1)
+		return &out, nil
+	}
+	if len(poly)-len(xs) > len(ks.SecretG1) {
+		return nil, fmt.Errorf("%w: polynomial of length %d is too large for setup of length %d",
+			ErrDomainTooSmall, len(poly), len(ks.SecretG1))
+	}
+	// quot = poly / Z_S
+	quotientPolynomial, err := ks.DivPoly(poly, VanishingPoly(xs))
+	if err != nil {
+		return nil, err
+	}
+	return bls.LinCombG1(ks.SecretG1[:len(quotientPolynomial)], quotientPolynomial), nil
+}
+
+// CheckProofMultiPoints checks a proof for a KZG commitment for the evaluations f(xs[i]) = ys[i],
+// at an arbitrary set of distinct points.
+func (ks *KZGSettings) CheckProofMultiPoints(commitment *bls.G1Point, proof *bls.G1Point, xs []bls.Fr, ys []bls.Fr) bool {
+	ok, err := ks.TryCheckProofMultiPoints(commitment, proof, xs, ys)
+	if err != nil {
+		panic(err)
+	}
+	return ok
+}
+
+// TryCheckProofMultiPoints is the error-returning variant of CheckProofMultiPoints.
+// An error is returned for malformed inputs, a false result for a proof that does not verify.
+func (ks *KZGSettings) TryCheckProofMultiPoints(commitment *bls.G1Point, proof *bls.G1Point, xs []bls.Fr, ys []bls.Fr) (bool, error) {
+	if len(xs) != len(ys) {
+		return false, fmt.Errorf("%w: got %d points and %d values", ErrLengthMismatch, len(xs), len(ys))
+	}
+	if len(xs) == 0 {
+		return false, fmt.Errorf("%w: no points to check", ErrSizeTooSmall)
+	}
+	if len(xs) >= len(ks.SecretG2) {
+		return false, fmt.Errorf("%w: got %d points, but setup only has %d G2 points", ErrDomainTooSmall, len(xs), len(ks.SecretG2))
+	}
+	if len(xs) > len(ks.SecretG1) {
+		return false, fmt.Errorf("%w: got %d points, but setup only has %d G1 points", ErrDomainTooSmall, len(xs), len(ks.SecretG1))
+	}
+	interpolationPoly, err := InterpolatePoly(xs, ys)
+	if err != nil {
+		return false, err
+	}
+	// [Z_S(s)]_2
+	zeroPoly := VanishingPoly(xs)
+	zs2 := bls.LinCombG2(ks.SecretG2[:len(zeroPoly)], zeroPoly)
+
+	// [interpolation_polynomial(s)]_1
+	is1 := bls.LinCombG1(ks.SecretG1[:len(interpolationPoly)], interpolationPoly)
+	// [commitment - interpolation_polynomial(s)]_1 = [commit]_1 - [interpolation_polynomial(s)]_1
+	var commitMinusInterpolation bls.G1Point
+	bls.SubG1(&commitMinusInterpolation, commitment, is1)
+
+	// Verify the pairing equation
+	//
+	// e([commitment - interpolation_polynomial(s)], [1]) = e([proof],  [Z_S(s)])
+	//    equivalent to
+	// e([commitment - interpolation_polynomial]^(-1), [1]) * e([proof],  [Z_S(s)]) = 1_T
+	//
+	return bls.PairingsVerify(&commitMinusInterpolation, &bls.GenG2, proof, zs2), nil
+}
+
+// checkDistinctPoints returns an error wrapping ErrDuplicatePoint if any point occurs more than once.
+func checkDistinctPoints(xs []bls.Fr) error {
+	seen := make(map[[32]byte]int, len(xs))
+	for i := range xs {
+		key := bls.FrTo32(&xs[i])
+		if j, ok := seen[key]; ok {
+			return fmt.Errorf("%w: points %d and %d are both %s", ErrDuplicatePoint, j, i, bls.FrStr(&xs[i]))
+		}
+		seen[key] = i
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestKZGSettings_CheckProofMultiPoints(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+
+	polynomial := testPoly(1, 2, 3, 4, 7, 7, 7, 7, 13, 13, 13, 13, 13, 13, 13, 13)
+	commitment := ks.CommitToPoly(polynomial)
+
+	for _, points := range [][]uint64{{42}, {3, 1000, 7}, {0, 5, 17, 123456, 9, 31}, {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}} {
+		xs := make([]bls.Fr, len(points))
+		ys := make([]bls.Fr, len(points))
+		for i, p := range points {
+			bls.AsFr(&xs[i], p)
+			bls.EvalPolyAt(&ys[i], polynomial, &xs[i])
+		}
+		proof, err := ks.TryComputeProofMultiPoints(polynomial, xs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ks.CheckProofMultiPoints(commitment, proof, xs, ys) {
+			t.Fatalf("could not verify proof for points %v", points)
+		}
+		// a wrong value must not verify
+		bls.AddModFr(&ys[0], &ys[0], &bls.ONE)
+		if ks.CheckProofMultiPoints(commitment, proof, xs, ys) {
+			t.Fatalf("verified proof with a wrong value for points %v", points)
+		}
+	}
+
+	xs := make([]bls.Fr, 3)
+	bls.AsFr(&xs[0], 1)
+	bls.AsFr(&xs[1], 2)
+	bls.AsFr(&xs[2], 1)
+	if _, err := ks.TryComputeProofMultiPoints(polynomial, xs); !errors.Is(err, ErrDuplicatePoint) {
+		t.Fatalf("expected duplicate point error, got: %v", err)
+	}
+	if _, err := ks.TryCheckProofMultiPoints(commitment, &bls.ZeroG1, xs, make([]bls.Fr, 3)); !errors.Is(err, ErrDuplicatePoint) {
+		t.Fatalf("expected duplicate point error, got: %v", err)
+	}
+}
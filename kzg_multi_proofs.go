@@ -5,65 +5,80 @@
 
 package kzg
 
-import "github.com/protolambda/go-kzg/bls"
+import (
+	"fmt"
+	"github.com/protolambda/go-kzg/bls"
+)
 
 // Compute KZG proof for polynomial in coefficient form at positions x * w^y where w is
 // an n-th root of unity (this is the proof for one data availability sample, which consists
 // of several polynomial evaluations)
 func (ks *KZGSettings) ComputeProofMulti(poly []bls.Fr, x uint64, n uint64) *bls.G1Point {
-	// divisor = [-pow(x, n, MODULUS)] + [0] * (n - 1) + [1]
-	divisor := make([]bls.Fr, n+1, n+1)
-	var xFr bls.Fr
-	bls.AsFr(&xFr, x)
-	// TODO: inefficient, could use squaring, or maybe BLS lib offers a power method?
-	// TODO: for small ranges, maybe compute pow(x, n, mod) in uint64?
-	var xPowN, tmp bls.Fr
-	for i := uint64(0); i < n; i++ {
-		bls.MulModFr(&tmp, &xPowN, &xFr)
-		bls.CopyFr(&xPowN, &tmp)
+	proof, err := ks.TryComputeProofMulti(poly, x, n)
+	if err != nil {
+		panic(err)
 	}
+	return proof
+}
 
-	// -pow(x, n, MODULUS)
-	bls.SubModFr(&divisor[0], &bls.ZERO, &xPowN)
-	// [0] * (n - 1)
-	for i := uint64(1); i < n; i++ {
-		bls.CopyFr(&divisor[i], &bls.ZERO)
+// TryComputeProofMulti is the error-returning variant of ComputeProofMulti
+func (ks *KZGSettings) TryComputeProofMulti(poly []bls.Fr, x uint64, n uint64) (*bls.G1Point, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("%w: cannot open at 0 points", ErrSizeTooSmall)
+	}
+	if uint64(len(poly)) < n+1 {
+		return nil, fmt.Errorf("%w: polynomial of length %d must be longer than the %d opened points",
+			ErrSizeTooSmall, len(poly), n)
 	}
-	// 1
-	bls.CopyFr(&divisor[n], &bls.ONE)
+	if uint64(len(poly))-n > uint64(len(ks.SecretG1)) {
+		return nil, fmt.Errorf("%w: polynomial of length %d is too large for setup of length %d",
+			ErrDomainTooSmall, len(poly), len(ks.SecretG1))
+	}
+	var xFr, xPowN bls.Fr
+	bls.AsFr(&xFr, x)
+	bls.ExpModFrUint64(&xPowN, &xFr, n)
 
-	// quot = poly / divisor
-	quotientPolynomial := polyLongDiv(poly, divisor[:])
-	//for i := 0; i < len(quotientPolynomial); i++ {
-	//	fmt.Printf("quot poly %d: %s\n", i, FrStr(&quotientPolynomial[i]))
-	//}
+	// quot = poly / (X^n - x^n), the divisor is sparse
+	quotientPolynomial, err := Poly(poly).DivSparse(n, &xPowN)
+	if err != nil {
+		return nil, err
+	}
 
 	// evaluate quotient poly at shared secret, in G1
-	return bls.LinCombG1(ks.SecretG1[:len(quotientPolynomial)], quotientPolynomial)
+	return bls.LinCombG1(ks.SecretG1[:len(quotientPolynomial)], quotientPolynomial), nil
 }
 
 // Check a proof for a KZG commitment for an evaluation f(x w^i) = y_i
 // The ys must have a power of 2 length
 func (ks *KZGSettings) CheckProofMulti(commitment *bls.G1Point, proof *bls.G1Point, x *bls.Fr, ys []bls.Fr) bool {
-	// Interpolate at a coset. Note because it is a coset, not the subgroup, we have to multiply the
-	// polynomial coefficients by x^i
-	interpolationPoly, err := ks.FFT(ys, true)
+	ok, err := ks.TryCheckProofMulti(commitment, proof, x, ys)
 	if err != nil {
-		panic("ys is bad, cannot compute FFT")
+		panic(err)
 	}
-	// TODO: can probably be optimized
-	// apply div(c, pow(x, i, MODULUS)) to every coeff c in interpolationPoly
-	// x^0 at first, then up to x^n
-	var xPow bls.Fr
-	bls.CopyFr(&xPow, &bls.ONE)
-	var tmp, tmp2 bls.Fr
-	for i := 0; i < len(interpolationPoly); i++ {
-		bls.InvModFr(&tmp, &xPow)
-		bls.MulModFr(&tmp2, &interpolationPoly[i], &tmp)
-		bls.CopyFr(&interpolationPoly[i], &tmp2)
-		bls.MulModFr(&tmp, &xPow, x)
-		bls.CopyFr(&xPow, &tmp)
+	return ok
+}
+
+// TryCheckProofMulti is the error-returning variant of CheckProofMulti.
+// An error is returned for malformed inputs, a false result for a proof that does not verify.
+func (ks *KZGSettings) TryCheckProofMulti(commitment *bls.G1Point, proof *bls.G1Point, x *bls.Fr, ys []bls.Fr) (bool, error) {
+	if !bls.IsPowerOfTwo(uint64(len(ys))) {
+		return false, fmt.Errorf("%w: expected ys length to be power of two, got %d", ErrNotPowerOfTwo, len(ys))
+	}
+	if len(ys) == 0 {
+		return false, fmt.Errorf("%w: no ys to check", ErrSizeTooSmall)
 	}
+	if len(ys) >= len(ks.SecretG2) {
+		return false, fmt.Errorf("%w: got %d ys, but setup only has %d G2 points", ErrDomainTooSmall, len(ys), len(ks.SecretG2))
+	}
+	// Interpolate at a coset. Note because it is a coset, not the subgroup, the coefficients are scaled by x^-i
+	coset := NewCosetPowers(x, uint64(len(ys)))
+	interpolationPoly, err := ks.CosetIFFT(ys, coset)
+	if err != nil {
+		return false, fmt.Errorf("ys is bad, cannot compute FFT: %w", err)
+	}
+	// x^n
+	var xPow bls.Fr
+	bls.MulModFr(&xPow, &coset.Powers[len(ys)-1], x)
 	// [x^n]_2
 	var xn2 bls.G2Point
 	bls.MulG2(&xn2, &bls.GenG2, &xPow)
@@ -84,5 +99,5 @@ func (ks *KZGSettings) CheckProofMulti(commitment *bls.G1Point, proof *bls.G1Poi
 	// e([commitment - interpolation_polynomial]^(-1), [1]) * e([proof],  [s^n - x^n]) = 1_T
 	//
 
-	return bls.PairingsVerify(&commitMinusInterpolation, &bls.GenG2, proof, &xnMinusYn)
+	return bls.PairingsVerify(&commitMinusInterpolation, &bls.GenG2, proof, &xnMinusYn), nil
 }
@@ -49,3 +49,31 @@ func TestKZGSettings_CheckProofMulti(t *testing.T) {
 		t.Fatal("could not verify proof")
 	}
 }
+
+func TestKZGSettings_CheckProofMultiHighDegree(t *testing.T) {
+	// The quotient of a polynomial of degree >= 2n depends on x^n, unlike for lower degrees.
+	fs := NewFFTSettings(6)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 64+1)
+	ks := NewKZGSettings(fs, s1, s2)
+	polynomial := make([]bls.Fr, 64, 64)
+	for i := range polynomial {
+		bls.AsFr(&polynomial[i], uint64(i*i+3))
+	}
+	commitment := ks.CommitToPoly(polynomial)
+
+	x := uint64(5431)
+	var xFr bls.Fr
+	bls.AsFr(&xFr, x)
+	n := uint64(8)
+	cosetKs := NewKZGSettings(NewFFTSettings(3), s1[:n+1], s2[:n+1])
+	ys := make([]bls.Fr, n, n)
+	for i := uint64(0); i < n; i++ {
+		var p bls.Fr
+		bls.MulModFr(&p, &xFr, &cosetKs.ExpandedRootsOfUnity[i])
+		bls.EvalPolyAt(&ys[i], polynomial, &p)
+	}
+	proof := ks.ComputeProofMulti(polynomial, x, n)
+	if !cosetKs.CheckProofMulti(commitment, proof, &xFr, ys) {
+		t.Fatal("could not verify proof")
+	}
+}
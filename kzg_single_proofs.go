@@ -5,7 +5,10 @@
 
 package kzg
 
-import "github.com/protolambda/go-kzg/bls"
+import (
+	"fmt"
+	"github.com/protolambda/go-kzg/bls"
+)
 
 // KZG commitment to polynomial in evaluation form, i.e. eval = FFT(coeffs).
 // The eval length must match the prepared KZG settings width.
@@ -34,23 +37,29 @@ func (ks *KZGSettings) CommitToPolyUnoptimized(coeffs []bls.Fr) *bls.G1Point {
 
 // Compute KZG proof for polynomial in coefficient form at position x
 func (ks *KZGSettings) ComputeProofSingle(poly []bls.Fr, x uint64) *bls.G1Point {
-	// divisor = [-x, 1]
-	divisor := [2]bls.Fr{}
-	var tmp bls.Fr
-	bls.AsFr(&tmp, x)
-	bls.SubModFr(&divisor[0], &bls.ZERO, &tmp)
-	bls.CopyFr(&divisor[1], &bls.ONE)
-	//for i := 0; i < 2; i++ {
-	//	fmt.Printf("div poly %d: %s\n", i, FrStr(&divisor[i]))
-	//}
-	// quot = poly / divisor
-	quotientPolynomial := polyLongDiv(poly, divisor[:])
-	//for i := 0; i < len(quotientPolynomial); i++ {
-	//	fmt.Printf("quot poly %d: %s\n", i, FrStr(&quotientPolynomial[i]))
-	//}
+	proof, err := ks.TryComputeProofSingle(poly, x)
+	if err != nil {
+		panic(err)
+	}
+	return proof
+}
+
+// TryComputeProofSingle is the error-returning variant of ComputeProofSingle
+func (ks *KZGSettings) TryComputeProofSingle(poly []bls.Fr, x uint64) (*bls.G1Point, error) {
+	if len(poly) == 0 {
+		return nil, fmt.Errorf("%w: empty polynomial", ErrSizeTooSmall)
+	}
+	if len(poly) > len(ks.SecretG1)+1 {
+		return nil, fmt.Errorf("%w: polynomial of length %d is too large for setup of length %d",
+			ErrDomainTooSmall, len(poly), len(ks.SecretG1))
+	}
+	var xFr bls.Fr
+	bls.AsFr(&xFr, x)
+	// quot = poly / (X - x), with synthetic division
+	quotientPolynomial := Poly(poly).DivLinear(&xFr)
 
 	// evaluate quotient poly at shared secret, in G1
-	return bls.LinCombG1(ks.SecretG1[:len(quotientPolynomial)], quotientPolynomial)
+	return bls.LinCombG1(ks.SecretG1[:len(quotientPolynomial)], quotientPolynomial), nil
 }
 
 // Check a proof for a KZG commitment for an evaluation f(x) = y
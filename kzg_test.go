@@ -0,0 +1,73 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTryNewKZGSettings(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	if _, err := TryNewKZGSettings(fs, s1, s2[:16]); !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected length mismatch error, got: %v", err)
+	}
+	if _, err := TryNewKZGSettings(fs, s1[:8], s2[:8]); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+	ks, err := TryNewKZGSettings(fs, s1, s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := TryNewFK20SingleSettings(ks, 32); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+	if _, err := TryNewFK20SingleSettings(ks, 12); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, err := TryNewFK20SingleSettings(ks, 16); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := TryNewFK20MultiSettings(ks, 16, 16); !errors.Is(err, ErrSizeTooLarge) {
+		t.Fatalf("expected size too large error, got: %v", err)
+	}
+	if _, err := TryNewFK20MultiSettings(ks, 16, 3); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, err := TryNewFK20MultiSettings(ks, 16, 0); !errors.Is(err, ErrSizeTooSmall) {
+		t.Fatalf("expected size too small error, got: %v", err)
+	}
+	if _, err := TryNewFK20MultiSettings(ks, 16, 4); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKZGSettings_TryProofs(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+	polynomial := testPoly(1, 2, 3, 4, 7, 7, 7, 7, 13, 13, 13, 13, 13, 13, 13, 13)
+
+	if _, err := ks.TryComputeProofSingle(nil, 17); !errors.Is(err, ErrSizeTooSmall) {
+		t.Fatalf("expected size too small error, got: %v", err)
+	}
+	if _, err := ks.TryComputeProofMulti(polynomial, 17, 16); !errors.Is(err, ErrSizeTooSmall) {
+		t.Fatalf("expected size too small error, got: %v", err)
+	}
+	commitment := ks.CommitToPoly(polynomial)
+	proof, err := ks.TryComputeProofMulti(polynomial, 17, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x = polynomial[0]
+	if _, err := ks.TryCheckProofMulti(commitment, proof, &x, polynomial[:3]); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, err := ks.TryCheckProofMulti(commitment, proof, &x, append(polynomial, polynomial...)); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// lagrangeCache holds the Lagrange form of the setup, per width. It is allocated separately from the settings,
+// so copies of the settings share it, instead of copying the lock.
+type lagrangeCache struct {
+	lock     sync.Mutex
+	natural  map[uint64][]bls.G1Point
+	reversed map[uint64][]bls.G1Point
+}
+
+func newLagrangeCache() *lagrangeCache {
+	return &lagrangeCache{
+		natural:  make(map[uint64][]bls.G1Point),
+		reversed: make(map[uint64][]bls.G1Point),
+	}
+}
+
+// LagrangeG1 returns the setup in Lagrange form for the domain of the given width: [L_i(s)]_1 for the
+// Lagrange basis polynomials L_i of the width-th roots of unity, in natural order.
+// It is derived from the first width SecretG1 points with an inverse FFT over G1, and cached for later calls,
+// if the settings were created with TryNewKZGSettings.
+// The returned points are shared, and must not be modified.
+func (ks *KZGSettings) LagrangeG1(width uint64) ([]bls.G1Point, error) {
+	if ks.lagrange == nil {
+		return ks.deriveLagrangeG1(width)
+	}
+	ks.lagrange.lock.Lock()
+	defer ks.lagrange.lock.Unlock()
+	return ks.lagrangeG1Locked(width)
+}
+
+// LagrangeG1BitReversed is like LagrangeG1, but returns the points in reverse bit order,
+// to commit to evaluations over the bit-reversed domain, as used in Ethereum.
+// The returned points are shared, and must not be modified.
+func (ks *KZGSettings) LagrangeG1BitReversed(width uint64) ([]bls.G1Point, error) {
+	if ks.lagrange == nil {
+		natural, err := ks.deriveLagrangeG1(width)
+		if err != nil {
+			return nil, err
+		}
+		reverseBitOrderG1(natural)
+		return natural, nil
+	}
+	ks.lagrange.lock.Lock()
+	defer ks.lagrange.lock.Unlock()
+	if out, ok := ks.lagrange.reversed[width]; ok {
+		return out, nil
+	}
+	natural, err := ks.lagrangeG1Locked(width)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bls.G1Point, len(natural))
+	copy(out, natural)
+	reverseBitOrderG1(out)
+	ks.lagrange.reversed[width] = out
+	return out, nil
+}
+
+func (ks *KZGSettings) lagrangeG1Locked(width uint64) ([]bls.G1Point, error) {
+	if out, ok := ks.lagrange.natural[width]; ok {
+		return out, nil
+	}
+	out, err := ks.deriveLagrangeG1(width)
+	if err != nil {
+		return nil, err
+	}
+	ks.lagrange.natural[width] = out
+	return out, nil
+}
+
+func (ks *KZGSettings) deriveLagrangeG1(width uint64) ([]bls.G1Point, error) {
+	if width == 0 || !bls.IsPowerOfTwo(width) {
+		return nil, fmt.Errorf("%w: width %d", ErrNotPowerOfTwo, width)
+	}
+	if width > uint64(len(ks.SecretG1)) {
+		return nil, fmt.Errorf("%w: width %d is larger than the setup of %d G1 points", ErrDomainTooSmall, width, len(ks.SecretG1))
+	}
+	return ks.FFTG1(ks.SecretG1[:width], true)
+}
+
+// CommitToEvalPoly commits to a polynomial in evaluation form, i.e. eval = FFT(coeffs),
+// over the roots of unity of the length of eval, in natural order.
+// Unlike the CommitToEvalPoly function, the Lagrange form of the setup is derived from the settings.
+// It panics if the length is not supported, see TryCommitToEvalPoly for an error-returning variant.
+func (ks *KZGSettings) CommitToEvalPoly(eval []bls.Fr) *bls.G1Point {
+	out, err := ks.TryCommitToEvalPoly(eval)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// TryCommitToEvalPoly is the error-returning variant of CommitToEvalPoly
+func (ks *KZGSettings) TryCommitToEvalPoly(eval []bls.Fr) (*bls.G1Point, error) {
+	lagrange, err := ks.LagrangeG1(uint64(len(eval)))
+	if err != nil {
+		return nil, err
+	}
+	return CommitToEvalPoly(lagrange, eval), nil
+}
+
+// ComputeProofSingleEval computes a KZG proof at the point z for the polynomial in evaluation form,
+// over the roots of unity of the length of eval in natural order, without converting it to coefficient form.
+// The point z may be inside of the domain. It returns the proof and the evaluation y = p(z).
+// The proof verifies with CheckProofSingle against the commitment of CommitToEvalPoly.
+// It panics if the length is not supported, see TryComputeProofSingleEval for an error-returning variant.
+func (ks *KZGSettings) ComputeProofSingleEval(eval []bls.Fr, z *bls.Fr) (proof *bls.G1Point, y *bls.Fr) {
+	proof, y, err := ks.TryComputeProofSingleEval(eval, z)
+	if err != nil {
+		panic(err)
+	}
+	return proof, y
+}
+
+// TryComputeProofSingleEval is the error-returning variant of ComputeProofSingleEval
+func (ks *KZGSettings) TryComputeProofSingleEval(eval []bls.Fr, z *bls.Fr) (proof *bls.G1Point, y *bls.Fr, err error) {
+	quotient, y, err := ks.ComputeEvalPolyQuotient(eval, z)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err = ks.TryCommitToEvalPoly(quotient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, y, nil
+}
@@ -0,0 +1,106 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"github.com/protolambda/go-kzg/bls"
+	"testing"
+)
+
+func TestKZGSettings_LagrangeG1(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+	for _, width := range []uint64{1, 2, 8, 16} {
+		polynomial := make([]bls.Fr, width)
+		for i := range polynomial {
+			bls.AsFr(&polynomial[i], uint64(i*i+3))
+		}
+		evalPoly, err := fs.FFT(polynomial, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitmentByCoeffs := ks.CommitToPoly(polynomial)
+		if commitmentByEval := ks.CommitToEvalPoly(evalPoly); !bls.EqualG1(commitmentByEval, commitmentByCoeffs) {
+			t.Fatalf("width %d: expected commitments to be equal, but got:\nby eval: %s\nby coeffs: %s",
+				width, commitmentByEval, commitmentByCoeffs)
+		}
+
+		reversedEval := make([]bls.Fr, width)
+		copy(reversedEval, evalPoly)
+		reverseBitOrderFr(reversedEval)
+		reversedLagrange, err := ks.LagrangeG1BitReversed(width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commitmentByEval := CommitToEvalPoly(reversedLagrange, reversedEval); !bls.EqualG1(commitmentByEval, commitmentByCoeffs) {
+			t.Fatalf("width %d: expected bit-reversed commitment to be equal, but got:\nby eval: %s\nby coeffs: %s",
+				width, commitmentByEval, commitmentByCoeffs)
+		}
+	}
+
+	a, err := ks.LagrangeG1(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ks.LagrangeG1(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &a[0] != &b[0] {
+		t.Fatal("expected the Lagrange points to be cached")
+	}
+	// copies of the settings share the cache
+	ksCopy := *ks
+	if c, err := ksCopy.LagrangeG1(16); err != nil || &c[0] != &a[0] {
+		t.Fatalf("expected the copy to share the cache, err: %v", err)
+	}
+	// settings without a cache derive the points on each call
+	uncached := &KZGSettings{FFTSettings: fs, SecretG1: s1, SecretG2: s2}
+	c, err := uncached.LagrangeG1BitReversed(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed, err := ks.LagrangeG1BitReversed(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range c {
+		if !bls.EqualG1(&c[i], &reversed[i]) {
+			t.Fatalf("uncached Lagrange point %d does not match", i)
+		}
+	}
+	if _, err := ks.LagrangeG1(3); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not a power of two error, got: %v", err)
+	}
+	if _, err := ks.LagrangeG1(32); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+}
+
+func TestKZGSettings_ComputeProofSingleEval(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+	polynomial := testPoly(1, 2, 3, 4, 7, 7, 7, 7, 13, 13, 13, 13, 13, 13, 13, 13)
+	evalPoly, err := fs.FFT(polynomial, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := ks.CommitToEvalPoly(evalPoly)
+	var outside bls.Fr
+	bls.AsFr(&outside, 17)
+	for _, z := range []*bls.Fr{&outside, &fs.ExpandedRootsOfUnity[3]} {
+		proof, y := ks.ComputeProofSingleEval(evalPoly, z)
+		if !ks.CheckProofSingle(commitment, proof, z, y) {
+			t.Fatalf("could not verify proof at %s", bls.FrStr(z))
+		}
+		if z == &outside {
+			if expected := ks.ComputeProofSingle(polynomial, 17); !bls.EqualG1(proof, expected) {
+				t.Fatalf("proof does not match the coefficient form proof")
+			}
+		}
+	}
+}
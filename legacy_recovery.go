@@ -7,44 +7,6 @@ import (
 	"github.com/protolambda/go-kzg/bls"
 )
 
-func (fs *FFTSettings) mulPolysWithFFT(a []bls.Fr, b []bls.Fr, rootsOfUnityStride uint64) []bls.Fr {
-	size := fs.MaxWidth / rootsOfUnityStride
-	aVals := make([]bls.Fr, size, size)
-	bVals := make([]bls.Fr, size, size)
-	for i := 0; i < len(a); i++ {
-		aVals[i] = a[i]
-	}
-	for i := len(a); i < len(aVals); i++ {
-		aVals[i] = bls.ZERO
-	}
-	for i := 0; i < len(b); i++ {
-		bVals[i] = b[i]
-	}
-	for i := len(b); i < len(bVals); i++ {
-		bVals[i] = bls.ZERO
-	}
-	rootz := fs.ExpandedRootsOfUnity[:fs.MaxWidth]
-	// Get FFT of a and b
-	x1 := make([]bls.Fr, len(aVals), len(aVals))
-	fs._fft(aVals, 0, 1, rootz, rootsOfUnityStride, x1)
-
-	x2 := make([]bls.Fr, len(bVals), len(bVals))
-	fs._fft(bVals, 0, 1, rootz, rootsOfUnityStride, x2)
-
-	// multiply the two. Hack: store results in x1
-	var tmp bls.Fr
-	for i := 0; i < len(x1); i++ {
-		bls.CopyFr(&tmp, &x1[i])
-		bls.MulModFr(&x1[i], &tmp, &x2[i])
-	}
-	revRootz := fs.ReverseRootsOfUnity[:fs.MaxWidth]
-
-	out := make([]bls.Fr, len(x1), len(x1))
-	// compute the FFT of the multiplied values.
-	fs._fft(x1, 0, 1, revRootz, rootsOfUnityStride, out)
-	return out
-}
-
 // Calculates modular inverses [1/values[0], 1/values[1] ...]
 func multiInv(values []bls.Fr) []bls.Fr {
 	partials := make([]bls.Fr, len(values)+1, len(values)+1)
@@ -64,19 +26,6 @@ func multiInv(values []bls.Fr) []bls.Fr {
 	return outputs
 }
 
-// Generates q(x) = poly(k * x)
-func pOfKX(poly []bls.Fr, k *bls.Fr) []bls.Fr {
-	out := make([]bls.Fr, len(poly), len(poly))
-	powerOfK := bls.ONE
-	var tmp bls.Fr
-	for i := range poly {
-		bls.MulModFr(&out[i], &poly[i], &powerOfK)
-		bls.CopyFr(&tmp, &powerOfK)
-		bls.MulModFr(&powerOfK, &tmp, k)
-	}
-	return out
-}
-
 func inefficientOddEvenDiv2(positions []uint64) (even []uint64, odd []uint64) { // TODO optimize away
 	for _, p := range positions {
 		if p&1 == 0 {
@@ -90,7 +39,7 @@ func inefficientOddEvenDiv2(positions []uint64) (even []uint64, odd []uint64) {
 
 // Return (x - root**positions[0]) * (x - root**positions[1]) * ...
 // possibly with a constant factor offset
-func (fs *FFTSettings) _zPoly(positions []uint64, rootsOfUnityStride uint64) []bls.Fr {
+func (fs *FFTSettings) _zPoly(positions []uint64, rootsOfUnityStride uint64) ([]bls.Fr, error) {
 	// If there are not more than 4 positions, use the naive
 	// O(n^2) algorithm as it is faster
 	if len(positions) <= 4 {
@@ -123,37 +72,24 @@ func (fs *FFTSettings) _zPoly(positions []uint64, rootsOfUnityStride uint64) []b
 		for i, j := 0, len(root)-1; i < j; i, j = i+1, j-1 {
 			root[i], root[j] = root[j], root[i]
 		}
-		return root
+		return root, nil
 	}
 	// Recursively find the zpoly for even indices and odd
 	// indices, operating over a half-size subgroup in each case
 	evenPositions, oddPositions := inefficientOddEvenDiv2(positions)
-	left := fs._zPoly(evenPositions, rootsOfUnityStride<<1)
-	right := fs._zPoly(oddPositions, rootsOfUnityStride<<1)
-	invRoot := &fs.ReverseRootsOfUnity[rootsOfUnityStride]
-	// Offset the result for the odd indices, and combine the two
-	out := fs.mulPolysWithFFT(left, pOfKX(right, invRoot), rootsOfUnityStride)
-	// Deal with the special case where mul_polys returns zero
-	// when it should return x ^ (2 ** k) - 1
-	isZero := true
-	for i := range out {
-		if !bls.EqualZero(&out[i]) {
-			isZero = false
-			break
-		}
+	left, err := fs._zPoly(evenPositions, rootsOfUnityStride<<1)
+	if err != nil {
+		return nil, err
 	}
-	if isZero {
-		// TODO: it's [1] + [0] * (len(out) - 1) + [modulus - 1] in python, but strange it's 1 larger than out
-		out[0] = bls.ONE
-		for i := 1; i < len(out); i++ {
-			out[i] = bls.ZERO
-		}
-		last := bls.MODULUS_MINUS1
-		out = append(out, last)
-		return out
-	} else {
-		return out
+	right, err := fs._zPoly(oddPositions, rootsOfUnityStride<<1)
+	if err != nil {
+		return nil, err
 	}
+	invRoot := &fs.ReverseRootsOfUnity[rootsOfUnityStride]
+	// Offset the result for the odd indices, and combine the two.
+	// The product is not reduced modulo x ^ (2 ** k) - 1, so if all positions of the subgroup are included,
+	// it is x ^ (2 ** k) - 1 itself, rather than zero.
+	return fs.MulPolys(left, Poly(right).ScaleX(invRoot))
 }
 
 // TODO test unhappy case
@@ -169,7 +105,11 @@ func (fs *FFTSettings) ErasureCodeRecover(vals []*bls.Fr) ([]bls.Fr, error) {
 		}
 	}
 	// TODO: handle len(positions)==0 case
-	z := fs._zPoly(positions, fs.MaxWidth/uint64(len(vals)))
+	z, err := fs._zPoly(positions, fs.MaxWidth/uint64(len(vals)))
+	if err != nil {
+		return nil, err
+	}
+	z = Poly(z).Pad(uint64(len(vals)))
 	//debugFrs("z", z)
 	zVals, err := fs.FFT(z, false)
 	if err != nil {
@@ -212,14 +152,14 @@ func (fs *FFTSettings) ErasureCodeRecover(vals []*bls.Fr) ([]bls.Fr, error) {
 		// Convert p_times_z(x) and z(x) into new polynomials
 		// q1(x) = p_times_z(k*x) and q2(x) = z(k*x)
 		// These are likely to not be 0 at any of the evaluation points.
-		pTimesZOfKX := pOfKX(pTimesZ, &kFr)
+		pTimesZOfKX := Poly(pTimesZ).ScaleX(&kFr)
 		//debugFrs("p_times_z_of_kx", pTimesZOfKX)
 		pTimesZOfKXVals, err := fs.FFT(pTimesZOfKX, false)
 		if err != nil {
 			return nil, err
 		}
 		//debugFrs("p_times_z_of_kx_vals", pTimesZOfKXVals)
-		zOfKX := pOfKX(z, &kFr)
+		zOfKX := Poly(z).ScaleX(&kFr)
 		//debugFrs("z_of_kx", zOfKX)
 		zOfKXVals, err := fs.FFT(zOfKX, false)
 		if err != nil {
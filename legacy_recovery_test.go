@@ -138,3 +138,32 @@ func TestErasureCodeRecover(t *testing.T) {
 		}
 	}
 }
+
+func TestErasureCodeRecoverFullSubgroupMissing(t *testing.T) {
+	fs := NewFFTSettings(7)
+	poly := make([]bls.Fr, fs.MaxWidth, fs.MaxWidth)
+	for i := uint64(0); i < fs.MaxWidth/2; i++ {
+		bls.AsFr(&poly[i], i+1)
+	}
+	for i := fs.MaxWidth / 2; i < fs.MaxWidth; i++ {
+		poly[i] = bls.ZERO
+	}
+	data, err := fs.FFT(poly, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// all odd positions are missing: the zero poly of the odd half is x^(n/2) - 1
+	subset := make([]*bls.Fr, fs.MaxWidth, fs.MaxWidth)
+	for i := 0; i < len(data); i += 2 {
+		subset[i] = &data[i]
+	}
+	recovered, err := fs.ErasureCodeRecover(subset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range recovered {
+		if got := &recovered[i]; !bls.EqualFr(got, &data[i]) {
+			t.Errorf("recovery at index %d got %s but expected %s", i, bls.FrStr(got), bls.FrStr(&data[i]))
+		}
+	}
+}
@@ -1,29 +1,44 @@
 package kzg
 
-import "github.com/protolambda/go-kzg/bls"
+import (
+	"fmt"
 
-// invert the divisor, then multiply
-func polyFactorDiv(dst *bls.Fr, a *bls.Fr, b *bls.Fr) {
-	// TODO: use divmod instead.
-	var tmp bls.Fr
-	bls.InvModFr(&tmp, b)
-	bls.MulModFr(dst, &tmp, a)
-}
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Poly is a polynomial in coefficient form: p[i] is the coefficient of X^i.
+// Methods do not modify the receiver or the arguments, results are newly allocated.
+// A Poly may have trailing zero coefficients, the zero polynomial may be empty.
+type Poly []bls.Fr
 
-// Long polynomial division for two polynomials in coefficient form
+// Long polynomial division for two polynomials in coefficient form.
+// The leading coefficient of the divisor must not be zero. The remainder is dropped.
 func polyLongDiv(dividend []bls.Fr, divisor []bls.Fr) []bls.Fr {
+	quot, _ := polyLongDivRem(dividend, divisor)
+	return quot
+}
+
+// Long polynomial division for two polynomials in coefficient form, also returning the remainder.
+// The leading coefficient of the divisor must not be zero.
+func polyLongDivRem(dividend []bls.Fr, divisor []bls.Fr) (quot []bls.Fr, rem []bls.Fr) {
 	a := make([]bls.Fr, len(dividend), len(dividend))
 	for i := 0; i < len(a); i++ {
 		bls.CopyFr(&a[i], &dividend[i])
 	}
+	if len(dividend) < len(divisor) {
+		return []bls.Fr{}, a
+	}
 	aPos := len(a) - 1
 	bPos := len(divisor) - 1
 	diff := aPos - bPos
 	out := make([]bls.Fr, diff+1, diff+1)
+	// invert the leading coefficient once, instead of dividing by it every step
+	var invLead bls.Fr
+	bls.InvModFr(&invLead, &divisor[bPos])
+	var tmp, tmp2 bls.Fr
 	for diff >= 0 {
 		quot := &out[diff]
-		polyFactorDiv(quot, &a[aPos], &divisor[bPos])
-		var tmp, tmp2 bls.Fr
+		bls.MulModFr(quot, &a[aPos], &invLead)
 		for i := bPos; i >= 0; i-- {
 			// In steps: a[diff + i] -= b[i] * quot
 			// tmp =  b[i] * quot
@@ -36,5 +51,438 @@ func polyLongDiv(dividend []bls.Fr, divisor []bls.Fr) []bls.Fr {
 		aPos -= 1
 		diff -= 1
 	}
+	// the remainder is what is left of the lower part
+	return out, a[:bPos]
+}
+
+// DivLinear divides the polynomial by (X - x) with synthetic division. The remainder, p(x), is dropped.
+// The quotient is one shorter than the polynomial.
+func (p Poly) DivLinear(x *bls.Fr) Poly {
+	if len(p) < 2 {
+		return Poly{}
+	}
+	out := make(Poly, len(p)-1, len(p)-1)
+	bls.CopyFr(&out[len(out)-1], &p[len(p)-1])
+	var tmp bls.Fr
+	for i := len(out) - 1; i > 0; i-- {
+		// out[i-1] = p[i] + x * out[i]
+		bls.MulModFr(&tmp, &out[i], x)
+		bls.AddModFr(&out[i-1], &p[i], &tmp)
+	}
+	return out
+}
+
+// VanishingPoly computes the coefficients of (X - xs[0]) * (X - xs[1]) * ... * (X - xs[n-1]), of length n+1
+func VanishingPoly(xs []bls.Fr) Poly {
+	out := make(Poly, len(xs)+1, len(xs)+1)
+	bls.CopyFr(&out[0], &bls.ONE)
+	var tmp, tmp2 bls.Fr
+	for i := range xs {
+		// multiply the first i+1 coefficients by (X - xs[i]), in-place from the top
+		bls.CopyFr(&out[i+1], &out[i])
+		for j := i; j > 0; j-- {
+			bls.MulModFr(&tmp, &out[j], &xs[i])
+			bls.SubModFr(&tmp2, &out[j-1], &tmp)
+			bls.CopyFr(&out[j], &tmp2)
+		}
+		bls.MulModFr(&tmp, &out[0], &xs[i])
+		bls.SubModFr(&out[0], &bls.ZERO, &tmp)
+	}
+	return out
+}
+
+// InterpolatePoly computes the coefficients of the polynomial of degree < len(xs) with p(xs[i]) = ys[i],
+// with Lagrange interpolation in O(n^2). The points must be distinct, or an error wrapping ErrDuplicatePoint is returned.
+// To interpolate over the roots of unity, use FFTSettings.EvalsToPoly instead.
+func InterpolatePoly(xs []bls.Fr, ys []bls.Fr) (Poly, error) {
+	if len(xs) != len(ys) {
+		return nil, fmt.Errorf("%w: got %d points and %d values", ErrLengthMismatch, len(xs), len(ys))
+	}
+	out := make(Poly, len(xs), len(xs))
+	for i := range out {
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+	zeroPoly := VanishingPoly(xs)
+	var denom, scale, tmp bls.Fr
+	for i := range xs {
+		// the zero poly without (X - xs[i]) is zero at all points but xs[i]
+		basis := zeroPoly.DivLinear(&xs[i])
+		bls.EvalPolyAt(&denom, basis, &xs[i])
+		if bls.EqualZero(&denom) {
+			return nil, fmt.Errorf("%w: point %d (%s) occurs more than once", ErrDuplicatePoint, i, bls.FrStr(&xs[i]))
+		}
+		bls.DivModFr(&scale, &ys[i], &denom)
+		for j := range basis {
+			bls.MulModFr(&tmp, &basis[j], &scale)
+			bls.AddModFr(&out[j], &out[j], &tmp)
+		}
+	}
+	return out, nil
+}
+
+// DivSparse divides the polynomial by the sparse divisor (X^n - c). The remainder is dropped.
+// The quotient is n shorter than the polynomial. This is O(len(p)), regardless of n.
+// For n = 0 the divisor is the constant 1 - c, and an error wrapping ErrZeroDivisor is returned if that is zero.
+func (p Poly) DivSparse(n uint64, c *bls.Fr) (Poly, error) {
+	if n == 0 {
+		var constant, inv bls.Fr
+		bls.SubModFr(&constant, &bls.ONE, c)
+		if bls.EqualZero(&constant) {
+			return nil, fmt.Errorf("%w: X^0 - 1", ErrZeroDivisor)
+		}
+		bls.InvModFr(&inv, &constant)
+		return p.Scale(&inv), nil
+	}
+	if uint64(len(p)) <= n {
+		return Poly{}, nil
+	}
+	// a is the running remainder, only the lower part of it changes
+	a := p.Copy()
+	out := make(Poly, uint64(len(p))-n, uint64(len(p))-n)
+	var tmp bls.Fr
+	for i := uint64(len(p)) - 1; i >= n; i-- {
+		// the leading term a[i] X^i = a[i] X^(i-n) (X^n - c) + c a[i] X^(i-n)
+		bls.CopyFr(&out[i-n], &a[i])
+		bls.MulModFr(&tmp, &a[i], c)
+		bls.AddModFr(&a[i-n], &a[i-n], &tmp)
+	}
+	return out, nil
+}
+
+// Divisors and quotients of at least this length are divided with FFTs, smaller ones with long division.
+const fftDivisionThreshold = 64
+
+// useFFTDivision returns true if the division is large enough to be faster with FFTs,
+// and the FFT settings are wide enough for it: the FFTs are at most twice the quotient length.
+func (fs *FFTSettings) useFFTDivision(dividendLen int, divisorLen int) bool {
+	quotientLen := dividendLen - divisorLen + 1
+	return divisorLen >= fftDivisionThreshold && quotientLen >= fftDivisionThreshold &&
+		nextPowOf2(uint64(2*quotientLen-1)) <= fs.MaxWidth
+}
+
+// DivPoly divides the dividend by the divisor, and drops the remainder.
+// The fastest method is picked: synthetic division for linear divisors, sparse division for X^n - c divisors,
+// FFT-based division for large divisors (if the FFT settings are wide enough), and long division otherwise.
+// An error wrapping ErrZeroDivisor is returned if the divisor is zero.
+func (fs *FFTSettings) DivPoly(dividend Poly, divisor Poly) (Poly, error) {
+	// high zero coefficients do not change the divisor
+	divisor = divisor.trim()
+	if len(divisor) == 0 {
+		return nil, ErrZeroDivisor
+	}
+	if len(dividend) < len(divisor) {
+		return Poly{}, nil
+	}
+	lead := &divisor[len(divisor)-1]
+	if bls.EqualOne(lead) {
+		if len(divisor) == 2 {
+			// X - x
+			var x bls.Fr
+			bls.SubModFr(&x, &bls.ZERO, &divisor[0])
+			return dividend.DivLinear(&x), nil
+		}
+		sparse := len(divisor) > 1
+		for i := 1; i < len(divisor)-1; i++ {
+			if !bls.EqualZero(&divisor[i]) {
+				sparse = false
+				break
+			}
+		}
+		if sparse {
+			// X^n - c
+			var c bls.Fr
+			bls.SubModFr(&c, &bls.ZERO, &divisor[0])
+			return dividend.DivSparse(uint64(len(divisor)-1), &c)
+		}
+	}
+	if fs.useFFTDivision(len(dividend), len(divisor)) {
+		if out, err := fs.polyDivFFT(dividend, divisor); err == nil {
+			return out, nil
+		}
+	}
+	return polyLongDiv(dividend, divisor), nil
+}
+
+// Polynomials of at least this length are multiplied with FFTs, smaller ones with schoolbook multiplication.
+const fftMulThreshold = 32
+
+// MulPolys multiplies two polynomials. Large polynomials are multiplied with FFTs of the next power of two
+// of the product length, an error wrapping ErrDomainTooSmall is returned if the settings are not wide enough for that.
+func (fs *FFTSettings) MulPolys(a Poly, b Poly) (Poly, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return Poly{}, nil
+	}
+	outLen := uint64(len(a) + len(b) - 1)
+	if len(a) < fftMulThreshold || len(b) < fftMulThreshold {
+		out := make(Poly, outLen, outLen)
+		for i := range out {
+			bls.CopyFr(&out[i], &bls.ZERO)
+		}
+		var tmp bls.Fr
+		for i := range a {
+			for j := range b {
+				bls.MulModFr(&tmp, &a[i], &b[j])
+				bls.AddModFr(&out[i+j], &out[i+j], &tmp)
+			}
+		}
+		return out, nil
+	}
+	n := nextPowOf2(outLen)
+	if n > fs.MaxWidth {
+		return nil, fmt.Errorf("%w: product of length %d needs %d roots of unity, only have %d", ErrDomainTooSmall, outLen, n, fs.MaxWidth)
+	}
+	aEval, err := fs.PolyToEvals(a, n)
+	if err != nil {
+		return nil, err
+	}
+	bEval, err := fs.PolyToEvals(b, n)
+	if err != nil {
+		return nil, err
+	}
+	var tmp bls.Fr
+	for i := range aEval {
+		bls.MulModFr(&tmp, &aEval[i], &bEval[i])
+		bls.CopyFr(&aEval[i], &tmp)
+	}
+	out, err := fs.FFT(aEval, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:outLen], nil
+}
+
+// polyInvSeries computes the first k coefficients of the power series inverse of b, i.e. b * out = 1 mod X^k,
+// with Newton iteration: g_2l = g_l * (2 - b * g_l) mod X^2l. The constant coefficient of b must not be zero.
+func (fs *FFTSettings) polyInvSeries(b Poly, k int) (Poly, error) {
+	g := make(Poly, 1, k)
+	bls.InvModFr(&g[0], &b[0])
+	for l := 1; l < k; {
+		l2 := 2 * l
+		if l2 > k {
+			l2 = k
+		}
+		bl := b
+		if len(bl) > l2 {
+			bl = bl[:l2]
+		}
+		t, err := fs.MulPolys(bl, g)
+		if err != nil {
+			return nil, err
+		}
+		// t = 2 - b * g mod X^l2
+		if len(t) > l2 {
+			t = t[:l2]
+		}
+		var tmp bls.Fr
+		for i := range t {
+			bls.SubModFr(&tmp, &bls.ZERO, &t[i])
+			bls.CopyFr(&t[i], &tmp)
+		}
+		bls.AddModFr(&tmp, &t[0], &bls.ONE)
+		bls.AddModFr(&t[0], &tmp, &bls.ONE)
+		g, err = fs.MulPolys(g, t)
+		if err != nil {
+			return nil, err
+		}
+		if len(g) > l2 {
+			g = g[:l2]
+		}
+		l = l2
+	}
+	return g, nil
+}
+
+// polyDivFFT divides with the reversed polynomials: rev(q) = rev(a) / rev(b) mod X^(len(a)-len(b)+1),
+// in O(n log n) with FFTs. The leading coefficient of the divisor must not be zero.
+func (fs *FFTSettings) polyDivFFT(dividend Poly, divisor Poly) (Poly, error) {
+	if len(dividend) < len(divisor) {
+		return Poly{}, nil
+	}
+	k := len(dividend) - len(divisor) + 1
+	reverse := func(p Poly, n int) Poly {
+		if n > len(p) {
+			n = len(p)
+		}
+		out := make(Poly, n, n)
+		for i := 0; i < n; i++ {
+			bls.CopyFr(&out[i], &p[len(p)-1-i])
+		}
+		return out
+	}
+	inv, err := fs.polyInvSeries(reverse(divisor, k), k)
+	if err != nil {
+		return nil, err
+	}
+	revQuot, err := fs.MulPolys(reverse(dividend, k), inv)
+	if err != nil {
+		return nil, err
+	}
+	return reverse(revQuot[:k], k), nil
+}
+
+// Copy returns a copy of the polynomial.
+func (p Poly) Copy() Poly {
+	out := make(Poly, len(p), len(p))
+	for i := range p {
+		bls.CopyFr(&out[i], &p[i])
+	}
+	return out
+}
+
+// Pad returns a copy of the polynomial, zero-padded to n coefficients. Longer polynomials are copied as-is.
+func (p Poly) Pad(n uint64) Poly {
+	if uint64(len(p)) > n {
+		n = uint64(len(p))
+	}
+	out := make(Poly, n, n)
+	p.padInto(out)
 	return out
 }
+
+// padInto copies the coefficients into out, and fills the remainder of out with zeroes.
+// out must be at least as long as the polynomial.
+func (p Poly) padInto(out []bls.Fr) {
+	for i := range p {
+		bls.CopyFr(&out[i], &p[i])
+	}
+	for i := len(p); i < len(out); i++ {
+		bls.CopyFr(&out[i], &bls.ZERO)
+	}
+}
+
+// trim returns the polynomial without trailing zero coefficients, sharing the coefficients.
+func (p Poly) trim() Poly {
+	for len(p) > 0 && bls.EqualZero(&p[len(p)-1]) {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+// Degree returns the degree of the polynomial, ignoring trailing zero coefficients. The zero polynomial has degree -1.
+func (p Poly) Degree() int {
+	return len(p.trim()) - 1
+}
+
+// Add returns p + q, of the length of the longest of the two.
+func (p Poly) Add(q Poly) Poly {
+	if len(p) < len(q) {
+		return q.Add(p)
+	}
+	out := p.Copy()
+	for i := range q {
+		bls.AddModFr(&out[i], &p[i], &q[i])
+	}
+	return out
+}
+
+// Sub returns p - q, of the length of the longest of the two.
+func (p Poly) Sub(q Poly) Poly {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	out := make(Poly, n, n)
+	for i := range out {
+		switch {
+		case i < len(p) && i < len(q):
+			bls.SubModFr(&out[i], &p[i], &q[i])
+		case i < len(p):
+			bls.CopyFr(&out[i], &p[i])
+		default:
+			bls.SubModFr(&out[i], &bls.ZERO, &q[i])
+		}
+	}
+	return out
+}
+
+// Scale returns c * p
+func (p Poly) Scale(c *bls.Fr) Poly {
+	out := make(Poly, len(p), len(p))
+	for i := range p {
+		bls.MulModFr(&out[i], &p[i], c)
+	}
+	return out
+}
+
+// Eval returns p(x)
+func (p Poly) Eval(x *bls.Fr) *bls.Fr {
+	var out bls.Fr
+	if len(p) == 0 {
+		bls.CopyFr(&out, &bls.ZERO)
+		return &out
+	}
+	bls.EvalPolyAt(&out, p, x)
+	return &out
+}
+
+// Derivative returns the formal derivative p'(X), one shorter than the polynomial.
+func (p Poly) Derivative() Poly {
+	if len(p) < 2 {
+		return Poly{}
+	}
+	out := make(Poly, len(p)-1, len(p)-1)
+	var iFr bls.Fr
+	for i := 1; i < len(p); i++ {
+		bls.AsFr(&iFr, uint64(i))
+		bls.MulModFr(&out[i-1], &p[i], &iFr)
+	}
+	return out
+}
+
+// ScaleX returns the composition p(k * X), i.e. coefficient i is multiplied by k^i.
+func (p Poly) ScaleX(k *bls.Fr) Poly {
+	out := make(Poly, len(p), len(p))
+	var powerOfK, tmp bls.Fr
+	bls.CopyFr(&powerOfK, &bls.ONE)
+	for i := range p {
+		bls.MulModFr(&out[i], &p[i], &powerOfK)
+		bls.MulModFr(&tmp, &powerOfK, k)
+		bls.CopyFr(&powerOfK, &tmp)
+	}
+	return out
+}
+
+// DivModPoly divides the dividend by the divisor, and returns the quotient and the remainder,
+// with dividend = quotient * divisor + remainder. The remainder is shorter than the trimmed divisor.
+// An error wrapping ErrZeroDivisor is returned if the divisor is zero.
+func (fs *FFTSettings) DivModPoly(dividend Poly, divisor Poly) (quotient Poly, remainder Poly, err error) {
+	divisor = divisor.trim()
+	if len(divisor) == 0 {
+		return nil, nil, ErrZeroDivisor
+	}
+	// the remainder is recovered from the product quotient * divisor, of the length of the dividend,
+	// which has to fit the FFT settings too
+	if !fs.useFFTDivision(len(dividend), len(divisor)) || nextPowOf2(uint64(len(dividend))) > fs.MaxWidth {
+		// the remainder is a by-product of long division
+		quotient, remainder = polyLongDivRem(dividend, divisor)
+		return quotient, remainder, nil
+	}
+	quotient, err = fs.DivPoly(dividend, divisor)
+	if err != nil {
+		return nil, nil, err
+	}
+	product, err := fs.MulPolys(quotient, divisor)
+	if err != nil {
+		return nil, nil, err
+	}
+	remainder = dividend.Sub(product)
+	if len(remainder) > len(divisor)-1 {
+		remainder = remainder[:len(divisor)-1]
+	}
+	return quotient, remainder, nil
+}
+
+// PolyToEvals evaluates the polynomial over the n-th roots of unity, in natural order, with an FFT.
+// The polynomial is zero-padded to n coefficients. n must be a power of two.
+func (fs *FFTSettings) PolyToEvals(p Poly, n uint64) ([]bls.Fr, error) {
+	if uint64(len(p)) > n {
+		return nil, fmt.Errorf("%w: polynomial of length %d does not fit in %d evaluations", ErrSizeTooLarge, len(p), n)
+	}
+	return fs.FFT(p.Pad(n), false)
+}
+
+// EvalsToPoly interpolates the polynomial from its evaluations over the roots of unity, in natural order,
+// with an inverse FFT. The length of the evaluations must be a power of two.
+func (fs *FFTSettings) EvalsToPoly(evals []bls.Fr) (Poly, error) {
+	return fs.FFT(evals, true)
+}
@@ -0,0 +1,238 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func randomPoly(n int) Poly {
+	out := make(Poly, n, n)
+	for i := range out {
+		bls.CopyFr(&out[i], bls.RandomFr())
+	}
+	return out
+}
+
+func checkPolyEqual(t *testing.T, got []bls.Fr, expected []bls.Fr) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("got length %d, expected %d", len(got), len(expected))
+	}
+	for i := range expected {
+		if !bls.EqualFr(&got[i], &expected[i]) {
+			t.Fatalf("difference at %d: got %s, expected %s", i, bls.FrStr(&got[i]), bls.FrStr(&expected[i]))
+		}
+	}
+}
+
+func TestPolyDiv(t *testing.T) {
+	fs := NewFFTSettings(10)
+	for _, dims := range [][2]int{{1, 1}, {5, 2}, {10, 2}, {10, 4}, {100, 9}, {300, 80}, {500, 200}, {1000, 100}, {50, 60}} {
+		dividend := randomPoly(dims[0])
+		generic := randomPoly(dims[1])
+		linear := randomPoly(2)
+		bls.CopyFr(&linear[1], &bls.ONE)
+		sparse := make([]bls.Fr, dims[1], dims[1])
+		for i := range sparse {
+			bls.CopyFr(&sparse[i], &bls.ZERO)
+		}
+		bls.CopyFr(&sparse[0], bls.RandomFr())
+		bls.CopyFr(&sparse[dims[1]-1], &bls.ONE)
+		for name, divisor := range map[string][]bls.Fr{"generic": generic, "linear": linear, "sparse": sparse} {
+			t.Run(fmt.Sprintf("%s_%d_by_%d", name, len(dividend), len(divisor)), func(t *testing.T) {
+				got, err := fs.DivPoly(dividend, divisor)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkPolyEqual(t, got, polyLongDiv(dividend, divisor))
+			})
+		}
+		if dims[1] >= fftDivisionThreshold && dims[0] > dims[1] && nextPowOf2(uint64(2*(dims[0]-dims[1])+1)) <= fs.MaxWidth {
+			got, err := fs.polyDivFFT(dividend, generic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPolyEqual(t, got, polyLongDiv(dividend, generic))
+		}
+	}
+	// an exact division returns the original factor
+	quot := randomPoly(200)
+	divisor := randomPoly(100)
+	product, err := fs.MulPolys(quot, divisor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fs.DivPoly(product, divisor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPolyEqual(t, got, quot)
+	checkPolyEqual(t, polyLongDiv(product, divisor), quot)
+
+	if _, err := fs.DivPoly(product, Poly{bls.ZERO, bls.ZERO}); !errors.Is(err, ErrZeroDivisor) {
+		t.Fatalf("expected zero divisor error, got: %v", err)
+	}
+}
+
+func TestPolyDivSparseConstant(t *testing.T) {
+	// X^0 - c is the constant 1 - c
+	p := randomPoly(10)
+	var c, constant bls.Fr
+	bls.AsFr(&c, 5)
+	bls.SubModFr(&constant, &bls.ONE, &c)
+	got, err := p.DivSparse(0, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPolyEqual(t, got.Scale(&constant), p)
+	if _, err := p.DivSparse(0, &bls.ONE); !errors.Is(err, ErrZeroDivisor) {
+		t.Fatalf("expected zero divisor error, got: %v", err)
+	}
+}
+
+func TestDivModPoly(t *testing.T) {
+	fs := NewFFTSettings(10)
+	// small divisions use long division, {300, 100} and {400, 64} reach the FFT division
+	for _, dims := range [][2]int{{1, 1}, {5, 2}, {50, 60}, {100, 9}, {300, 100}, {400, 64}, {1000, 100}} {
+		t.Run(fmt.Sprintf("%d_by_%d", dims[0], dims[1]), func(t *testing.T) {
+			dividend := randomPoly(dims[0])
+			divisor := randomPoly(dims[1])
+			expectedQuot, expectedRem := polyLongDivRem(dividend, divisor)
+			quot, rem, err := fs.DivModPoly(dividend, divisor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPolyEqual(t, quot, expectedQuot)
+			checkPolyEqual(t, rem, expectedRem)
+			if len(rem) >= len(divisor) {
+				t.Fatalf("expected remainder shorter than the divisor, got length %d", len(rem))
+			}
+			// dividend = quotient * divisor + remainder
+			product, err := fs.MulPolys(quot, divisor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPolyEqual(t, product.Add(rem).trim(), Poly(dividend).trim())
+		})
+	}
+	// the long division remainder of a shorter dividend is the dividend itself
+	dividend := randomPoly(5)
+	quot, rem := polyLongDivRem(dividend, randomPoly(8))
+	if len(quot) != 0 {
+		t.Fatalf("expected empty quotient, got length %d", len(quot))
+	}
+	checkPolyEqual(t, rem, dividend)
+	if _, _, err := fs.DivModPoly(dividend, Poly{bls.ZERO}); !errors.Is(err, ErrZeroDivisor) {
+		t.Fatalf("expected zero divisor error, got: %v", err)
+	}
+}
+
+func TestDivModPolyNarrowDomain(t *testing.T) {
+	// the quotient fits the FFT settings, but the product quotient * divisor does not
+	fs := NewFFTSettings(9)
+	dividend := randomPoly(1255)
+	divisor := randomPoly(1000)
+	expectedQuot, expectedRem := polyLongDivRem(dividend, divisor)
+	gotQuot, gotRem, err := fs.DivModPoly(dividend, divisor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPolyEqual(t, gotQuot, expectedQuot)
+	checkPolyEqual(t, gotRem, expectedRem)
+}
+
+func TestPolyArithmetic(t *testing.T) {
+	fs := NewFFTSettings(8)
+	a := randomPoly(50)
+	b := randomPoly(20)
+	var x, k bls.Fr
+	bls.AsFr(&x, 1234)
+	bls.AsFr(&k, 7)
+	ax, bx := a.Eval(&x), b.Eval(&x)
+	check := func(name string, p Poly, expected *bls.Fr) {
+		t.Helper()
+		if got := p.Eval(&x); !bls.EqualFr(got, expected) {
+			t.Fatalf("%s: got %s, expected %s", name, bls.FrStr(got), bls.FrStr(expected))
+		}
+	}
+	var expected bls.Fr
+	bls.AddModFr(&expected, ax, bx)
+	check("add", a.Add(b), &expected)
+	check("add_reversed", b.Add(a), &expected)
+	bls.SubModFr(&expected, ax, bx)
+	check("sub", a.Sub(b), &expected)
+	bls.SubModFr(&expected, bx, ax)
+	check("sub_reversed", b.Sub(a), &expected)
+	bls.MulModFr(&expected, ax, &k)
+	check("scale", a.Scale(&k), &expected)
+	for _, sizes := range [][2]int{{50, 20}, {3, 5}, {40, 40}} {
+		p, q := randomPoly(sizes[0]), randomPoly(sizes[1])
+		product, err := fs.MulPolys(p, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bls.MulModFr(&expected, p.Eval(&x), q.Eval(&x))
+		check(fmt.Sprintf("mul_%d_%d", sizes[0], sizes[1]), product, &expected)
+	}
+	var kx bls.Fr
+	bls.MulModFr(&kx, &k, &x)
+	check("scale_x", a.ScaleX(&k), a.Eval(&kx))
+
+	// derivative of X^3 + 2X is 3X^2 + 2
+	var two, three bls.Fr
+	bls.AsFr(&two, 2)
+	bls.AsFr(&three, 3)
+	checkPolyEqual(t, Poly{bls.ZERO, two, bls.ZERO, bls.ONE}.Derivative(), Poly{two, bls.ZERO, three})
+
+	quot, rem, err := fs.DivModPoly(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rem) != len(b)-1 {
+		t.Fatalf("expected remainder of length %d, got %d", len(b)-1, len(rem))
+	}
+	bls.MulModFr(&expected, quot.Eval(&x), bx)
+	bls.AddModFr(&expected, &expected, rem.Eval(&x))
+	check("divmod", a, &expected)
+
+	evals, err := fs.PolyToEvals(b, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := fs.EvalsToPoly(evals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Degree() != b.Degree() {
+		t.Fatalf("expected degree %d, got %d", b.Degree(), back.Degree())
+	}
+	checkPolyEqual(t, back[:len(b)], b)
+	if Poly(nil).Degree() != -1 || (Poly{bls.ZERO}).Degree() != -1 {
+		t.Fatal("expected degree -1 for the zero polynomial")
+	}
+}
+
+func TestInterpolatePoly(t *testing.T) {
+	poly := randomPoly(9)
+	xs := randomPoly(9)
+	ys := make([]bls.Fr, len(xs), len(xs))
+	for i := range xs {
+		bls.EvalPolyAt(&ys[i], poly, &xs[i])
+	}
+	got, err := InterpolatePoly(xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPolyEqual(t, got, poly)
+	zero := VanishingPoly(xs)
+	for i := range xs {
+		var v bls.Fr
+		bls.EvalPolyAt(&v, zero, &xs[i])
+		if !bls.EqualZero(&v) {
+			t.Fatalf("vanishing poly is not zero at point %d", i)
+		}
+	}
+}
@@ -5,55 +5,62 @@ import (
 	"github.com/protolambda/go-kzg/bls"
 )
 
-// unshift poly, in-place. Multiplies each coeff with 1/shift_factor**i
+// RecoveryCoset returns the powers of the coset shift used by RecoverPolyFromSamples: 1/5, up to MaxWidth.
+// They are computed on each call, which is O(MaxWidth), negligible next to the FFTs of the recovery.
+func (fs *FFTSettings) RecoveryCoset() *CosetPowers {
+	return newRecoveryCoset(fs.MaxWidth)
+}
+
+// shift poly, in-place. Multiplies each coeff with 1/shift_factor**i
 func (fs *FFTSettings) ShiftPoly(poly []bls.Fr) {
-	var shiftFactor bls.Fr
-	bls.AsFr(&shiftFactor, 5) // primitive root of unity
-	var factorPower bls.Fr
-	bls.CopyFr(&factorPower, &bls.ONE)
-	var invFactor bls.Fr
-	bls.InvModFr(&invFactor, &shiftFactor)
-	var tmp bls.Fr
-	for i := 0; i < len(poly); i++ {
-		bls.CopyFr(&tmp, &poly[i])
-		bls.MulModFr(&poly[i], &tmp, &factorPower)
-		// TODO: pre-compute all these shift scalars
-		bls.CopyFr(&tmp, &factorPower)
-		bls.MulModFr(&factorPower, &tmp, &invFactor)
-	}
+	_ = newRecoveryCoset(uint64(len(poly))).scale(poly, false)
 }
 
 // unshift poly, in-place. Multiplies each coeff with shift_factor**i
 func (fs *FFTSettings) UnshiftPoly(poly []bls.Fr) {
-	var shiftFactor bls.Fr
-	bls.AsFr(&shiftFactor, 5) // primitive root of unity
-	var factorPower bls.Fr
-	bls.CopyFr(&factorPower, &bls.ONE)
-	var tmp bls.Fr
-	for i := 0; i < len(poly); i++ {
-		bls.CopyFr(&tmp, &poly[i])
-		bls.MulModFr(&poly[i], &tmp, &factorPower)
-		// TODO: pre-compute all these shift scalars
-		bls.CopyFr(&tmp, &factorPower)
-		bls.MulModFr(&factorPower, &tmp, &shiftFactor)
-	}
+	_ = newRecoveryCoset(uint64(len(poly))).scale(poly, true)
 }
 
+// RecoverPolyFromSamples recovers the full data from the available samples (nil if missing),
+// using zeroPolyFn to compute the zero polynomial for the missing indices.
+// An error is returned if the samples are malformed or cannot be recovered, the function does not panic on bad input.
 func (fs *FFTSettings) RecoverPolyFromSamples(samples []*bls.Fr, zeroPolyFn ZeroPolyFn) ([]bls.Fr, error) {
 	// TODO: using a single additional temporary array, all the FFTs can run in-place.
 
+	if uint64(len(samples)) > fs.MaxWidth {
+		return nil, fmt.Errorf("%w: got %d samples, max width is %d", ErrDomainTooSmall, len(samples), fs.MaxWidth)
+	}
+	if !bls.IsPowerOfTwo(uint64(len(samples))) {
+		return nil, fmt.Errorf("%w: got %d samples", ErrNotPowerOfTwo, len(samples))
+	}
+
 	missingIndices := make([]uint64, 0, len(samples))
 	for i, s := range samples {
 		if s == nil {
 			missingIndices = append(missingIndices, uint64(i))
 		}
 	}
+	if len(missingIndices) == len(samples) {
+		return nil, fmt.Errorf("%w: all %d samples are missing", ErrSizeTooSmall, len(samples))
+	}
+	if len(missingIndices) == 0 {
+		// nothing to recover
+		out := make([]bls.Fr, len(samples), len(samples))
+		for i, s := range samples {
+			bls.CopyFr(&out[i], s)
+		}
+		return out, nil
+	}
 
 	zeroEval, zeroPoly := zeroPolyFn(missingIndices, uint64(len(samples)))
+	if len(zeroEval) != len(samples) || len(zeroPoly) != len(samples) {
+		return nil, fmt.Errorf("%w: zero poly (eval %d, coeffs %d) does not match samples (%d)",
+			ErrLengthMismatch, len(zeroEval), len(zeroPoly), len(samples))
+	}
 
 	for i, s := range samples {
 		if (s == nil) != bls.EqualZero(&zeroEval[i]) {
-			panic("bad zero eval")
+			return nil, fmt.Errorf("%w: bad zero eval at index %d, sample missing: %v", ErrInvalidZeroPoly, i, s == nil)
 		}
 	}
 
@@ -69,18 +76,14 @@ func (fs *FFTSettings) RecoverPolyFromSamples(samples []*bls.Fr, zeroPolyFn Zero
 	if err != nil {
 		return nil, err
 	}
-	// shift in-place
-	fs.ShiftPoly(polyWithZero)
-	shiftedPolyWithZero := polyWithZero
-
-	fs.ShiftPoly(zeroPoly)
-	shiftedZeroPoly := zeroPoly
-
-	evalShiftedPolyWithZero, err := fs.FFT(shiftedPolyWithZero, false)
+	// Evaluate over a coset, where the zero poly has no zeroes, to divide it out.
+	// only the powers up to the number of samples are needed, not up to MaxWidth.
+	coset := newRecoveryCoset(uint64(len(samples)))
+	evalShiftedPolyWithZero, err := fs.CosetFFT(polyWithZero, coset)
 	if err != nil {
 		return nil, err
 	}
-	evalShiftedZeroPoly, err := fs.FFT(shiftedZeroPoly, false)
+	evalShiftedZeroPoly, err := fs.CosetFFT(zeroPoly, coset)
 	if err != nil {
 		return nil, err
 	}
@@ -89,12 +92,10 @@ func (fs *FFTSettings) RecoverPolyFromSamples(samples []*bls.Fr, zeroPolyFn Zero
 	for i := 0; i < len(evalShiftedReconstructedPoly); i++ {
 		bls.DivModFr(&evalShiftedReconstructedPoly[i], &evalShiftedPolyWithZero[i], &evalShiftedZeroPoly[i])
 	}
-	shiftedReconstructedPoly, err := fs.FFT(evalShiftedReconstructedPoly, true)
+	reconstructedPoly, err := fs.CosetIFFT(evalShiftedReconstructedPoly, coset)
 	if err != nil {
 		return nil, err
 	}
-	fs.UnshiftPoly(shiftedReconstructedPoly)
-	reconstructedPoly := shiftedReconstructedPoly
 
 	reconstructedData, err := fs.FFT(reconstructedPoly, false)
 	if err != nil {
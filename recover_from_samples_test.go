@@ -1,6 +1,7 @@
 package kzg
 
 import (
+	"errors"
 	"fmt"
 	"github.com/protolambda/go-kzg/bls"
 	"math/rand"
@@ -135,3 +136,35 @@ func TestFFTSettings_RecoverPolyFromSamples(t *testing.T) {
 		}
 	}
 }
+
+func TestFFTSettings_RecoverPolyFromSamples_BadInput(t *testing.T) {
+	fs := NewFFTSettings(3)
+	if _, err := fs.RecoverPolyFromSamples(make([]*bls.Fr, 16), fs.ZeroPolyViaMultiplication); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+	if _, err := fs.RecoverPolyFromSamples(make([]*bls.Fr, 6), fs.ZeroPolyViaMultiplication); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, err := fs.RecoverPolyFromSamples(make([]*bls.Fr, 8), fs.ZeroPolyViaMultiplication); !errors.Is(err, ErrSizeTooSmall) {
+		t.Fatalf("expected size too small error, got: %v", err)
+	}
+	badZeroPolyFn := func(missingIndices []uint64, length uint64) ([]bls.Fr, []bls.Fr) {
+		return make([]bls.Fr, length), make([]bls.Fr, length)
+	}
+	samples := make([]*bls.Fr, 8)
+	samples[2] = &bls.ONE
+	if _, err := fs.RecoverPolyFromSamples(samples, badZeroPolyFn); !errors.Is(err, ErrInvalidZeroPoly) {
+		t.Fatalf("expected invalid zero poly error, got: %v", err)
+	}
+	// the zero poly must vanish at the missing samples, not only avoid the available ones
+	onesZeroPolyFn := func(missingIndices []uint64, length uint64) ([]bls.Fr, []bls.Fr) {
+		evals := make([]bls.Fr, length)
+		for i := range evals {
+			bls.CopyFr(&evals[i], &bls.ONE)
+		}
+		return evals, make([]bls.Fr, length)
+	}
+	if _, err := fs.RecoverPolyFromSamples(samples, onesZeroPolyFn); !errors.Is(err, ErrInvalidZeroPoly) {
+		t.Fatalf("expected invalid zero poly error, got: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package kzg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// The settings can be encoded with MarshalBinary, to persist precomputed tables, and decoded with UnmarshalBinary.
+// Every encoding is framed the same way:
+//
+//	magic (4 bytes) | version (1 byte) | body | SHA-256 checksum of everything before it (32 bytes)
+//
+// Integers in the body are big-endian uint64 values, field elements are 32 bytes little-endian (like bls.FrTo32),
+// and points are uncompressed (see bls.ToUncompressedG1), so decoding skips the square roots of decompression.
+const encodingVersion = 1
+
+const (
+	fftSettingsMagic        = "FFTS"
+	kzgSettingsMagic        = "KZGS"
+	fk20SingleSettingsMagic = "FK1S"
+	fk20MultiSettingsMagic  = "FKMS"
+)
+
+// sealEncoding frames the body with the magic, version and checksum.
+func sealEncoding(magic string, body []byte) []byte {
+	out := make([]byte, 0, len(magic)+1+len(body)+sha256.Size)
+	out = append(out, magic...)
+	out = append(out, encodingVersion)
+	out = append(out, body...)
+	sum := sha256.Sum256(out)
+	return append(out, sum[:]...)
+}
+
+// openEncoding checks the magic, version and checksum of the encoding, and returns the body.
+func openEncoding(magic string, data []byte) ([]byte, error) {
+	if len(data) < len(magic)+1+sha256.Size {
+		return nil, fmt.Errorf("%w: %d bytes is too short", ErrInvalidEncoding, len(data))
+	}
+	if string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: expected magic %q, got %q", ErrInvalidEncoding, magic, data[:len(magic)])
+	}
+	if v := data[len(magic)]; v != encodingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, v)
+	}
+	end := len(data) - sha256.Size
+	if sum := sha256.Sum256(data[:end]); !bytes.Equal(sum[:], data[end:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidEncoding)
+	}
+	return data[len(magic)+1 : end], nil
+}
+
+// encoder appends the body of an encoding.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uint64(v uint64) {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], v)
+	e.buf = append(e.buf, tmp[:]...)
+}
+
+func (e *encoder) bytes(v []byte) {
+	e.buf = append(e.buf, v...)
+}
+
+// decoder reads the body of an encoding. After the first error, reads return zero values, and err is kept.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if uint64(len(d.data)) < n {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidEncoding)
+		return nil
+	}
+	out := d.data[:n]
+	d.data = d.data[n:]
+	return out
+}
+
+func (d *decoder) uint64() uint64 {
+	b := d.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// finish returns the first error, or an error if not all data was read.
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.err = fmt.Errorf("%w: %d unexpected trailing bytes", ErrInvalidEncoding, len(d.data))
+	}
+	return d.err
+}
+
+// The roots of unity are not encoded, they are quick to derive from the scale.
+// The root of unity is encoded only to check that the decoded settings use the same one.
+func (fs *FFTSettings) encode(e *encoder) {
+	e.uint64(uint64(bits.TrailingZeros64(fs.MaxWidth)))
+	e.uint64(uint64(fs.Algorithm))
+	root := bls.FrTo32(fs.RootOfUnity)
+	e.bytes(root[:])
+}
+
+// fftParams are the decoded FFT settings, checked but not yet expanded into roots of unity,
+// so the size can be checked against the rest of the data before allocating anything.
+type fftParams struct {
+	scale     uint8
+	algorithm FFTAlgorithm
+}
+
+func decodeFFTParams(d *decoder, maxScale uint8) (fftParams, error) {
+	scale := d.uint64()
+	algorithm := d.uint64()
+	root := d.bytes(32)
+	if d.err != nil {
+		return fftParams{}, d.err
+	}
+	if scale > uint64(maxScale) || scale >= uint64(len(bls.Scale2RootOfUnity)) {
+		return fftParams{}, fmt.Errorf("%w: scale %d", ErrSizeTooLarge, scale)
+	}
+	if algorithm > uint64(FFTIterativeRadix4) {
+		return fftParams{}, fmt.Errorf("%w: unknown FFT algorithm %d", ErrInvalidEncoding, algorithm)
+	}
+	if expected := bls.FrTo32(&bls.Scale2RootOfUnity[scale]); !bytes.Equal(expected[:], root) {
+		return fftParams{}, fmt.Errorf("%w: root of unity does not match scale %d", ErrInvalidEncoding, scale)
+	}
+	return fftParams{scale: uint8(scale), algorithm: FFTAlgorithm(algorithm)}, nil
+}
+
+func (p fftParams) settings() *FFTSettings {
+	fs := NewFFTSettings(p.scale)
+	fs.Algorithm = p.algorithm
+	return fs
+}
+
+// MarshalBinary encodes the settings, see UnmarshalBinary.
+func (fs *FFTSettings) MarshalBinary() ([]byte, error) {
+	var e encoder
+	fs.encode(&e)
+	return sealEncoding(fftSettingsMagic, e.buf), nil
+}
+
+// UnmarshalBinary decodes settings encoded with MarshalBinary, and overwrites fs with them.
+// The returned error wraps ErrInvalidEncoding if the data is malformed or corrupted.
+//
+// The encoding is tiny, but the settings allocate the roots of unity of the encoded scale, up to 2**31 of them,
+// and the checksum does not authenticate the data. Use DecodeFFTSettings to bound the scale of untrusted data.
+func (fs *FFTSettings) UnmarshalBinary(data []byte) error {
+	out, err := DecodeFFTSettings(data, uint8(len(bls.Scale2RootOfUnity)-1))
+	if err != nil {
+		return err
+	}
+	*fs = *out
+	return nil
+}
+
+// DecodeFFTSettings decodes settings encoded with MarshalBinary, and returns an error wrapping ErrSizeTooLarge
+// if the scale is larger than maxScale, before allocating the settings.
+func DecodeFFTSettings(data []byte, maxScale uint8) (*FFTSettings, error) {
+	body, err := openEncoding(fftSettingsMagic, data)
+	if err != nil {
+		return nil, err
+	}
+	d := &decoder{data: body}
+	params, err := decodeFFTParams(d, maxScale)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return params.settings(), nil
+}
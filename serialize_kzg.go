@@ -0,0 +1,240 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func encodeG1Points(e *encoder, points []bls.G1Point) {
+	for i := range points {
+		e.bytes(bls.ToUncompressedG1(&points[i]))
+	}
+}
+
+func encodeG2Points(e *encoder, points []bls.G2Point) {
+	for i := range points {
+		e.bytes(bls.ToUncompressedG2(&points[i]))
+	}
+}
+
+func decodeG1Points(d *decoder, n uint64, allowInfinity bool) ([]bls.G1Point, error) {
+	if d.err == nil && n > uint64(len(d.data))/bls.G1UncompressedSize {
+		d.err = fmt.Errorf("%w: %d G1 points do not fit in the remaining data", ErrInvalidEncoding, n)
+	}
+	data := d.bytes(n * bls.G1UncompressedSize)
+	if d.err != nil {
+		return nil, d.err
+	}
+	out := make([]bls.G1Point, n, n)
+	for i := range out {
+		p, err := bls.DecodeUncompressedG1(data[uint64(i)*bls.G1UncompressedSize:uint64(i+1)*bls.G1UncompressedSize], allowInfinity)
+		if err != nil {
+			return nil, fmt.Errorf("%w: G1 point %d: %v", ErrInvalidEncoding, i, err)
+		}
+		bls.CopyG1(&out[i], p)
+	}
+	return out, nil
+}
+
+func decodeG2Points(d *decoder, n uint64, allowInfinity bool) ([]bls.G2Point, error) {
+	if d.err == nil && n > uint64(len(d.data))/bls.G2UncompressedSize {
+		d.err = fmt.Errorf("%w: %d G2 points do not fit in the remaining data", ErrInvalidEncoding, n)
+	}
+	data := d.bytes(n * bls.G2UncompressedSize)
+	if d.err != nil {
+		return nil, d.err
+	}
+	out := make([]bls.G2Point, n, n)
+	for i := range out {
+		p, err := bls.DecodeUncompressedG2(data[uint64(i)*bls.G2UncompressedSize:uint64(i+1)*bls.G2UncompressedSize], allowInfinity)
+		if err != nil {
+			return nil, fmt.Errorf("%w: G2 point %d: %v", ErrInvalidEncoding, i, err)
+		}
+		bls.CopyG2(&out[i], p)
+	}
+	return out, nil
+}
+
+// MarshalBinary encodes the FFT settings and the setup points, see UnmarshalBinary.
+// The Lagrange form of the setup is not encoded, it is derived again on demand.
+func (ks *KZGSettings) MarshalBinary() ([]byte, error) {
+	var e encoder
+	ks.FFTSettings.encode(&e)
+	e.uint64(uint64(len(ks.SecretG1)))
+	encodeG1Points(&e, ks.SecretG1)
+	e.uint64(uint64(len(ks.SecretG2)))
+	encodeG2Points(&e, ks.SecretG2)
+	return sealEncoding(kzgSettingsMagic, e.buf), nil
+}
+
+// UnmarshalBinary decodes settings encoded with MarshalBinary, and overwrites ks with them.
+// The setup points are checked to be valid points in their subgroup, but not to be powers of a single secret:
+// use VerifySetup for that. The returned error wraps ErrInvalidEncoding if the data is malformed or corrupted.
+func (ks *KZGSettings) UnmarshalBinary(data []byte) error {
+	body, err := openEncoding(kzgSettingsMagic, data)
+	if err != nil {
+		return err
+	}
+	d := &decoder{data: body}
+	params, err := decodeFFTParams(d, uint8(len(bls.Scale2RootOfUnity)-1))
+	if err != nil {
+		return err
+	}
+	// the FFT settings are only allocated after the setup points are read,
+	// which must be at least as many as the width, and fit in the data.
+	n1 := d.uint64()
+	if d.err == nil && uint64(1)<<params.scale > n1 {
+		return fmt.Errorf("%w: expected at least %d setup points, got %d", ErrDomainTooSmall, uint64(1)<<params.scale, n1)
+	}
+	// the setup points are never the point at infinity
+	secretG1, err := decodeG1Points(d, n1, false)
+	if err != nil {
+		return err
+	}
+	secretG2, err := decodeG2Points(d, d.uint64(), false)
+	if err != nil {
+		return err
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	fs := params.settings()
+	out, err := TryNewKZGSettings(fs, secretG1, secretG2)
+	if err != nil {
+		return err
+	}
+	*ks = *out
+	return nil
+}
+
+// setupDigest hashes the first n G1 setup points, from which the FK20 tables of extended size 2n are computed.
+// The encoded tables are bound to the setup with it.
+func (ks *KZGSettings) setupDigest(n uint64) (out [32]byte) {
+	h := sha256.New()
+	for i := uint64(0); i < n; i++ {
+		h.Write(bls.ToCompressedG1(&ks.SecretG1[i]))
+	}
+	copy(out[:], h.Sum(nil))
+	return
+}
+
+// checkSetupDigest checks the encoded digest against the setup of the settings, before decoding the tables of extended size n2.
+func (ks *KZGSettings) checkSetupDigest(d *decoder, n2 uint64) error {
+	digest := d.bytes(32)
+	if d.err != nil {
+		return d.err
+	}
+	if expected := ks.setupDigest(n2 / 2); !bytes.Equal(expected[:], digest) {
+		return fmt.Errorf("%w: the tables were computed with a different setup", ErrInvalidSetup)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the precomputed FK20 table, see UnmarshalBinary.
+// The KZG settings are not included, encode those separately.
+func (fk *FK20SingleSettings) MarshalBinary() ([]byte, error) {
+	n2 := uint64(len(fk.xExtFFT))
+	var e encoder
+	e.uint64(n2)
+	digest := fk.setupDigest(n2 / 2)
+	e.bytes(digest[:])
+	encodeG1Points(&e, fk.xExtFFT)
+	return sealEncoding(fk20SingleSettingsMagic, e.buf), nil
+}
+
+// UnmarshalBinary decodes a table encoded with MarshalBinary, for the KZG settings fk already has,
+// e.g. fk := &FK20SingleSettings{KZGSettings: ks}. The returned error wraps ErrInvalidEncoding
+// if the data is malformed or corrupted, and ErrInvalidSetup if the table was computed with a different setup.
+func (fk *FK20SingleSettings) UnmarshalBinary(data []byte) error {
+	if fk.KZGSettings == nil {
+		return fmt.Errorf("%w: no KZG settings to decode the table for", ErrInvalidSetup)
+	}
+	body, err := openEncoding(fk20SingleSettingsMagic, data)
+	if err != nil {
+		return err
+	}
+	d := &decoder{data: body}
+	n2 := d.uint64()
+	if d.err != nil {
+		return d.err
+	}
+	if err := fk.checkExtendedSize(n2); err != nil {
+		return err
+	}
+	if err := fk.checkSetupDigest(d, n2); err != nil {
+		return err
+	}
+	xExtFFT, err := decodeG1Points(d, n2, true)
+	if err != nil {
+		return err
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	fk.xExtFFT = xExtFFT
+	fk.updateBases = &updateBasesCache{}
+	return nil
+}
+
+// MarshalBinary encodes the precomputed FK20 tables, see UnmarshalBinary.
+// The KZG settings are not included, encode those separately.
+func (fk *FK20MultiSettings) MarshalBinary() ([]byte, error) {
+	n2 := uint64(len(fk.xExtFFTFiles[0])) * fk.chunkLen
+	var e encoder
+	e.uint64(n2)
+	e.uint64(fk.chunkLen)
+	digest := fk.setupDigest(n2 / 2)
+	e.bytes(digest[:])
+	for _, xExtFFT := range fk.xExtFFTFiles {
+		encodeG1Points(&e, xExtFFT)
+	}
+	return sealEncoding(fk20MultiSettingsMagic, e.buf), nil
+}
+
+// UnmarshalBinary decodes tables encoded with MarshalBinary, for the KZG settings fk already has,
+// e.g. fk := &FK20MultiSettings{KZGSettings: ks}. The returned error wraps ErrInvalidEncoding
+// if the data is malformed or corrupted, and ErrInvalidSetup if the tables were computed with a different setup.
+func (fk *FK20MultiSettings) UnmarshalBinary(data []byte) error {
+	if fk.KZGSettings == nil {
+		return fmt.Errorf("%w: no KZG settings to decode the tables for", ErrInvalidSetup)
+	}
+	body, err := openEncoding(fk20MultiSettingsMagic, data)
+	if err != nil {
+		return err
+	}
+	d := &decoder{data: body}
+	n2 := d.uint64()
+	chunkLen := d.uint64()
+	if d.err != nil {
+		return d.err
+	}
+	if err := fk.checkExtendedSize(n2); err != nil {
+		return err
+	}
+	if err := checkChunkLen(n2, chunkLen); err != nil {
+		return err
+	}
+	if err := fk.checkSetupDigest(d, n2); err != nil {
+		return err
+	}
+	xExtFFTFiles := make([][]bls.G1Point, chunkLen, chunkLen)
+	for i := range xExtFFTFiles {
+		xExtFFT, err := decodeG1Points(d, n2/chunkLen, true)
+		if err != nil {
+			return err
+		}
+		xExtFFTFiles[i] = xExtFFT
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	fk.chunkLen = chunkLen
+	fk.xExtFFTFiles = xExtFFTFiles
+	return nil
+}
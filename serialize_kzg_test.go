@@ -0,0 +1,113 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestKZGSettings_MarshalBinary(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(fs, s1, s2)
+	data, err := ks.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out KZGSettings
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if out.MaxWidth != ks.MaxWidth || len(out.SecretG1) != len(ks.SecretG1) || len(out.SecretG2) != len(ks.SecretG2) {
+		t.Fatal("decoded settings do not match")
+	}
+	for i := range ks.SecretG1 {
+		if !bls.EqualG1(&out.SecretG1[i], &ks.SecretG1[i]) || !bls.EqualG2(&out.SecretG2[i], &ks.SecretG2[i]) {
+			t.Fatalf("decoded setup point %d does not match", i)
+		}
+	}
+	if err := out.VerifySetup(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a large scale is rejected before allocating the FFT settings, if there are not as many setup points
+	forged := encoder{buf: forgedFFTSettings(31)}
+	forged.uint64(uint64(len(s1)))
+	encodeG1Points(&forged, s1)
+	forged.uint64(uint64(len(s2)))
+	encodeG2Points(&forged, s2)
+	if err := out.UnmarshalBinary(sealEncoding(kzgSettingsMagic, forged.buf)); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected scale to be rejected, got %v", err)
+	}
+
+	truncated := sealEncoding(kzgSettingsMagic, data[5:len(data)-32-1])
+	if err := out.UnmarshalBinary(truncated); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("expected invalid encoding error, got %v", err)
+	}
+}
+
+func TestFK20Settings_MarshalBinary(t *testing.T) {
+	fs := NewFFTSettings(5)
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 32+1)
+	ks := NewKZGSettings(fs, s1, s2)
+	otherS1, otherS2 := GenerateTestingSetup("1234", 32+1)
+	otherKs := NewKZGSettings(fs, otherS1, otherS2)
+	polynomial := testPoly(1, 2, 3, 4, 7, 7, 7, 7, 13, 13, 13, 13, 13, 13, 13, 13)
+
+	t.Run("single", func(t *testing.T) {
+		fk := NewFK20SingleSettings(ks, 32)
+		data, err := fk.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := &FK20SingleSettings{KZGSettings: ks}
+		if err := out.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		expected, got := fk.DAUsingFK20(polynomial), out.DAUsingFK20(polynomial)
+		for i := range expected {
+			if !bls.EqualG1(&expected[i], &got[i]) {
+				t.Fatalf("proof %d does not match", i)
+			}
+		}
+		if err := (&FK20SingleSettings{KZGSettings: otherKs}).UnmarshalBinary(data); !errors.Is(err, ErrInvalidSetup) {
+			t.Fatalf("expected setup mismatch error, got %v", err)
+		}
+		if err := (&FK20SingleSettings{}).UnmarshalBinary(data); !errors.Is(err, ErrInvalidSetup) {
+			t.Fatalf("expected missing setup error, got %v", err)
+		}
+		data[len(data)-1] ^= 1
+		if err := out.UnmarshalBinary(data); !errors.Is(err, ErrInvalidEncoding) {
+			t.Fatalf("expected invalid encoding error, got %v", err)
+		}
+	})
+
+	t.Run("multi", func(t *testing.T) {
+		fk := NewFK20MultiSettings(ks, 32, 4)
+		data, err := fk.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := &FK20MultiSettings{KZGSettings: ks}
+		if err := out.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		expected, got := fk.DAUsingFK20Multi(polynomial), out.DAUsingFK20Multi(polynomial)
+		for i := range expected {
+			if !bls.EqualG1(&expected[i], &got[i]) {
+				t.Fatalf("proof %d does not match", i)
+			}
+		}
+		if err := (&FK20MultiSettings{KZGSettings: otherKs}).UnmarshalBinary(data); !errors.Is(err, ErrInvalidSetup) {
+			t.Fatalf("expected setup mismatch error, got %v", err)
+		}
+		// the single and multi tables are not interchangeable
+		if err := (&FK20SingleSettings{KZGSettings: ks}).UnmarshalBinary(data); !errors.Is(err, ErrInvalidEncoding) {
+			t.Fatalf("expected invalid encoding error, got %v", err)
+		}
+	})
+}
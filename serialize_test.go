@@ -0,0 +1,76 @@
+package kzg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestFFTSettings_MarshalBinary(t *testing.T) {
+	fs := NewFFTSettings(6)
+	fs.Algorithm = FFTIterativeRadix4
+	data, err := fs.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out FFTSettings
+	if err := out.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if out.MaxWidth != fs.MaxWidth || out.Algorithm != fs.Algorithm || !bls.EqualFr(out.RootOfUnity, fs.RootOfUnity) {
+		t.Fatalf("decoded settings do not match: width %d, algorithm %s", out.MaxWidth, out.Algorithm)
+	}
+	if len(out.ExpandedRootsOfUnity) != len(fs.ExpandedRootsOfUnity) || len(out.ReverseRootsOfUnity) != len(fs.ReverseRootsOfUnity) {
+		t.Fatal("decoded roots of unity do not match")
+	}
+	for i := range fs.ExpandedRootsOfUnity {
+		if !bls.EqualFr(&out.ExpandedRootsOfUnity[i], &fs.ExpandedRootsOfUnity[i]) || !bls.EqualFr(&out.ReverseRootsOfUnity[i], &fs.ReverseRootsOfUnity[i]) {
+			t.Fatalf("decoded root of unity %d does not match", i)
+		}
+	}
+
+	corrupt := func(f func(v []byte) []byte) []byte {
+		return f(append([]byte{}, data...))
+	}
+	cases := map[string][]byte{
+		"empty":     nil,
+		"truncated": data[:len(data)-1],
+		"magic":     corrupt(func(v []byte) []byte { v[0] = 'X'; return v }),
+		"version":   corrupt(func(v []byte) []byte { v[4] = encodingVersion + 1; return v }),
+		"checksum":  corrupt(func(v []byte) []byte { v[len(v)-1] ^= 1; return v }),
+		"body":      corrupt(func(v []byte) []byte { v[12] ^= 1; return v }),
+		// a valid frame around a body of the wrong root of unity, and of trailing data
+		"root":     sealEncoding(fftSettingsMagic, corrupt(func(v []byte) []byte { v[21] ^= 1; return v })[5:len(data)-32]),
+		"trailing": sealEncoding(fftSettingsMagic, append(corrupt(func(v []byte) []byte { return v })[5:len(data)-32], 0)),
+	}
+	for name, v := range cases {
+		if err := out.UnmarshalBinary(v); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("%s: expected invalid encoding error, got %v", name, err)
+		}
+	}
+}
+
+// forgedFFTSettings encodes the body of settings of the given scale, without allocating them.
+func forgedFFTSettings(scale uint8) []byte {
+	var e encoder
+	e.uint64(uint64(scale))
+	e.uint64(uint64(FFTRecursive))
+	root := bls.FrTo32(&bls.Scale2RootOfUnity[scale])
+	e.bytes(root[:])
+	return e.buf
+}
+
+func TestDecodeFFTSettings(t *testing.T) {
+	data := sealEncoding(fftSettingsMagic, forgedFFTSettings(31))
+	if _, err := DecodeFFTSettings(data, 12); !errors.Is(err, ErrSizeTooLarge) {
+		t.Fatalf("expected scale to be rejected, got %v", err)
+	}
+	fs, err := DecodeFFTSettings(sealEncoding(fftSettingsMagic, forgedFFTSettings(12)), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.MaxWidth != 1<<12 {
+		t.Fatalf("unexpected width %d", fs.MaxWidth)
+	}
+}
@@ -3,7 +3,11 @@
 
 package kzg
 
-import "github.com/protolambda/go-kzg/bls"
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
 
 // GenerateTestingSetup creates a setup of n values from the given secret. **for testing purposes only**
 func GenerateTestingSetup(secret string, n uint64) ([]bls.G1Point, []bls.G2Point) {
@@ -24,3 +28,100 @@ func GenerateTestingSetup(secret string, n uint64) ([]bls.G1Point, []bls.G2Point
 	}
 	return s1Out, s2Out
 }
+
+// VerifySetup checks that setupG1[i] = [s^i]_1 and setupG2[i] = [s^i]_2 for a single secret s,
+// and that all points are valid points in their subgroup. The returned error wraps ErrInvalidSetup.
+//
+// The power structure is verified with two randomized pairing checks:
+// with random r_i, e(sum_i r_i * setupG1[i], [s]_2) = e(sum_i r_i * setupG1[i+1], [1]_2), and likewise for G2.
+func VerifySetup(setupG1 []bls.G1Point, setupG2 []bls.G2Point) error {
+	if len(setupG1) < 2 || len(setupG2) < 2 {
+		return fmt.Errorf("%w: need at least 2 G1 and G2 points, got %d and %d", ErrInvalidSetup, len(setupG1), len(setupG2))
+	}
+	for i := range setupG1 {
+		if !bls.IsValidG1(&setupG1[i]) {
+			return fmt.Errorf("%w: G1 point %d is not in the G1 subgroup", ErrInvalidSetup, i)
+		}
+	}
+	for i := range setupG2 {
+		if !bls.IsValidG2(&setupG2[i]) {
+			return fmt.Errorf("%w: G2 point %d is not in the G2 subgroup", ErrInvalidSetup, i)
+		}
+	}
+	if !bls.EqualG1(&setupG1[0], &bls.GenG1) {
+		return fmt.Errorf("%w: G1 point 0 is not the generator", ErrInvalidSetup)
+	}
+	if !bls.EqualG2(&setupG2[0], &bls.GenG2) {
+		return fmt.Errorf("%w: G2 point 0 is not the generator", ErrInvalidSetup)
+	}
+	// a secret of 0 or 1 would pass the power checks, but is trivially broken
+	if bls.EqualG1(&setupG1[1], &bls.ZeroG1) || bls.EqualG1(&setupG1[1], &bls.GenG1) {
+		return fmt.Errorf("%w: degenerate secret", ErrInvalidSetup)
+	}
+
+	n := len(setupG1) - 1
+	rs := make([]bls.Fr, n)
+	for i := range rs {
+		bls.CopyFr(&rs[i], bls.RandomFr())
+	}
+	lo := bls.LinCombG1(setupG1[:n], rs)
+	hi := bls.LinCombG1(setupG1[1:], rs)
+	// e(lo, [s]_2) = e(hi, [1]_2)
+	if !bls.PairingsVerify(hi, &setupG2[0], lo, &setupG2[1]) {
+		return fmt.Errorf("%w: G1 points are not consecutive powers of the secret of the G2 points", ErrInvalidSetup)
+	}
+
+	m := len(setupG2) - 1
+	rs2 := make([]bls.Fr, m)
+	for i := range rs2 {
+		bls.CopyFr(&rs2[i], bls.RandomFr())
+	}
+	loG2 := bls.LinCombG2(setupG2[:m], rs2)
+	hiG2 := bls.LinCombG2(setupG2[1:], rs2)
+	// e([s]_1, lo) = e([1]_1, hi)
+	if !bls.PairingsVerify(&setupG1[0], hiG2, &setupG1[1], loG2) {
+		return fmt.Errorf("%w: G2 points are not consecutive powers of the secret of the G1 points", ErrInvalidSetup)
+	}
+	return nil
+}
+
+// VerifyLagrangeSetup checks that setupLagrange is the Lagrange form of setupG1 over the roots of unity of
+// the length of setupLagrange, in natural order: setupLagrange[i] = [L_i(s)]_1, i.e. the inverse FFT of setupG1.
+// The setupG1 points are assumed to be verified with VerifySetup. The returned error wraps ErrInvalidSetup.
+//
+// With random r, the check is sum_i r_i * setupLagrange[i] = sum_j c_j * setupG1[j], where c is the inverse FFT of r,
+// which avoids the expensive FFT over G1.
+func (fs *FFTSettings) VerifyLagrangeSetup(setupG1 []bls.G1Point, setupLagrange []bls.G1Point) error {
+	n := uint64(len(setupLagrange))
+	if n == 0 || !bls.IsPowerOfTwo(n) {
+		return fmt.Errorf("%w: expected a power of two Lagrange points, got %d", ErrInvalidSetup, n)
+	}
+	if n > fs.MaxWidth {
+		return fmt.Errorf("%w: got %d Lagrange points but only have %d roots of unity", ErrDomainTooSmall, n, fs.MaxWidth)
+	}
+	if uint64(len(setupG1)) < n {
+		return fmt.Errorf("%w: got %d Lagrange points but only %d G1 points", ErrInvalidSetup, n, len(setupG1))
+	}
+	for i := range setupLagrange {
+		if !bls.IsValidG1(&setupLagrange[i]) {
+			return fmt.Errorf("%w: Lagrange point %d is not in the G1 subgroup", ErrInvalidSetup, i)
+		}
+	}
+	rs := make([]bls.Fr, n)
+	for i := range rs {
+		bls.CopyFr(&rs[i], bls.RandomFr())
+	}
+	cs, err := fs.FFT(rs, true)
+	if err != nil {
+		return err
+	}
+	if !bls.EqualG1(bls.LinCombG1(setupLagrange, rs), bls.LinCombG1(setupG1[:n], cs)) {
+		return fmt.Errorf("%w: Lagrange points are not the inverse FFT of the G1 points", ErrInvalidSetup)
+	}
+	return nil
+}
+
+// VerifySetup checks the setup of the settings with VerifySetup.
+func (ks *KZGSettings) VerifySetup() error {
+	return VerifySetup(ks.SecretG1, ks.SecretG2)
+}
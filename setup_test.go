@@ -0,0 +1,65 @@
+//go:build !bignum_pure && !bignum_hol256
+// +build !bignum_pure,!bignum_hol256
+
+package kzg
+
+import (
+	"errors"
+	"github.com/protolambda/go-kzg/bls"
+	"testing"
+)
+
+func TestVerifySetup(t *testing.T) {
+	s1, s2 := GenerateTestingSetup("1927409816240961209460912649124", 16+1)
+	ks := NewKZGSettings(NewFFTSettings(4), s1, s2)
+	if err := ks.VerifySetup(); err != nil {
+		t.Fatal(err)
+	}
+
+	swapped := append([]bls.G1Point{}, s1...)
+	swapped[3], swapped[4] = swapped[4], swapped[3]
+	if err := VerifySetup(swapped, s2); !errors.Is(err, ErrInvalidSetup) {
+		t.Fatalf("expected invalid setup error for swapped G1 points, got: %v", err)
+	}
+	_, otherS2 := GenerateTestingSetup("1234", 16+1)
+	if err := VerifySetup(s1, otherS2); !errors.Is(err, ErrInvalidSetup) {
+		t.Fatalf("expected invalid setup error for G2 points of another secret, got: %v", err)
+	}
+	tamperedS2 := append([]bls.G2Point{}, s2...)
+	bls.AddG2(&tamperedS2[7], &tamperedS2[7], &bls.GenG2)
+	if err := VerifySetup(s1, tamperedS2); !errors.Is(err, ErrInvalidSetup) {
+		t.Fatalf("expected invalid setup error for tampered G2 point, got: %v", err)
+	}
+	trivialS1, trivialS2 := GenerateTestingSetup("1", 4)
+	if err := VerifySetup(trivialS1, trivialS2); !errors.Is(err, ErrInvalidSetup) {
+		t.Fatalf("expected invalid setup error for trivial secret, got: %v", err)
+	}
+}
+
+func TestFFTSettings_VerifyLagrangeSetup(t *testing.T) {
+	fs := NewFFTSettings(4)
+	s1, _ := GenerateTestingSetup("1927409816240961209460912649124", 16)
+	lagrange, err := fs.FFTG1(s1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.VerifyLagrangeSetup(s1, lagrange); err != nil {
+		t.Fatal(err)
+	}
+	// a smaller domain uses the first monomial points
+	half, err := NewFFTSettings(3).FFTG1(s1[:8], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.VerifyLagrangeSetup(s1, half); err != nil {
+		t.Fatal(err)
+	}
+	reversed := append([]bls.G1Point{}, lagrange...)
+	reverseBitOrderG1(reversed)
+	if err := fs.VerifyLagrangeSetup(s1, reversed); !errors.Is(err, ErrInvalidSetup) {
+		t.Fatalf("expected invalid setup error for bit-reversed Lagrange points, got: %v", err)
+	}
+	if err := fs.VerifyLagrangeSetup(s1, lagrange[:5]); !errors.Is(err, ErrInvalidSetup) {
+		t.Fatalf("expected invalid setup error for non power of two length, got: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package kzg
+
+import (
+	"fmt"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+// Number of points per leaf of the subproduct tree. Leaves are built, evaluated and interpolated directly.
+const subproductTreeLeafSize = 16
+
+// SubproductTree is the tree of vanishing polynomials of a set of points: the leaves vanish on small chunks
+// of the points, every parent is the product of its children, and the root vanishes on all of the points.
+// It is used for multipoint evaluation and interpolation in O(n log^2 n), instead of O(n^2),
+// and can be reused for any number of polynomials over the same points.
+type SubproductTree struct {
+	fs *FFTSettings
+	// points per leaf
+	leafPoints [][]bls.Fr
+	// levels[0] are the leaves, the last level has only the root.
+	levels [][]Poly
+}
+
+// NewSubproductTree builds the subproduct tree of the points. The leaves are built directly,
+// and the children are multiplied with MulPolys, which uses FFTs for the larger products.
+// The FFT settings must be at least as wide as the next power of two of the number of points.
+func (fs *FFTSettings) NewSubproductTree(xs []bls.Fr) (*SubproductTree, error) {
+	if len(xs) == 0 {
+		return nil, fmt.Errorf("%w: no points", ErrSizeTooSmall)
+	}
+	if n := nextPowOf2(uint64(len(xs)) + 1); n > fs.MaxWidth {
+		return nil, fmt.Errorf("%w: %d points need %d roots of unity, only have %d", ErrDomainTooSmall, len(xs), n, fs.MaxWidth)
+	}
+	t := &SubproductTree{fs: fs}
+	var leaves []Poly
+	for start := 0; start < len(xs); start += subproductTreeLeafSize {
+		end := start + subproductTreeLeafSize
+		if end > len(xs) {
+			end = len(xs)
+		}
+		t.leafPoints = append(t.leafPoints, xs[start:end])
+		leaves = append(leaves, VanishingPoly(xs[start:end]))
+	}
+	t.levels = append(t.levels, leaves)
+	for level := leaves; len(level) > 1; {
+		next := make([]Poly, 0, (len(level)+1)/2)
+		for i := 0; i+1 < len(level); i += 2 {
+			prod, err := fs.MulPolys(level[i], level[i+1])
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, prod)
+		}
+		if len(level)%2 == 1 {
+			// the odd one out moves up a level as-is
+			next = append(next, level[len(level)-1])
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Root returns the vanishing polynomial of all the points of the tree.
+func (t *SubproductTree) Root() Poly {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// remainders computes the remainders of p by all the leaves, from the top of the tree down.
+func (t *SubproductTree) remainders(p Poly) ([]Poly, error) {
+	rems := []Poly{p}
+	for l := len(t.levels) - 1; l >= 0; l-- {
+		level := t.levels[l]
+		next := make([]Poly, len(level))
+		for i := range level {
+			// children 2i and 2i+1 have parent i, the odd one out is moved up to the last position, also i/2.
+			parent := rems[i/2]
+			if len(parent) < len(level[i]) {
+				next[i] = parent
+				continue
+			}
+			_, rem, err := t.fs.DivModPoly(parent, level[i])
+			if err != nil {
+				return nil, err
+			}
+			next[i] = rem
+		}
+		rems = next
+	}
+	return rems, nil
+}
+
+// Evaluate evaluates the polynomial at all the points of the tree, in order.
+func (t *SubproductTree) Evaluate(p Poly) ([]bls.Fr, error) {
+	rems, err := t.remainders(p)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bls.Fr, 0, len(t.leafPoints)*subproductTreeLeafSize)
+	for i, points := range t.leafPoints {
+		for j := range points {
+			out = append(out, *rems[i].Eval(&points[j]))
+		}
+	}
+	return out, nil
+}
+
+// Interpolate computes the polynomial of degree < len(ys) with p(xs[i]) = ys[i], for the points xs of the tree.
+// An error wrapping ErrDuplicatePoint is returned if the points are not distinct.
+func (t *SubproductTree) Interpolate(ys []bls.Fr) (Poly, error) {
+	count := 0
+	for _, points := range t.leafPoints {
+		count += len(points)
+	}
+	if len(ys) != count {
+		return nil, fmt.Errorf("%w: got %d values for %d points", ErrLengthMismatch, len(ys), count)
+	}
+	// p(X) = sum_i ys[i] / M'(xs[i]) * M(X) / (X - xs[i]), with M the root
+	weights, err := t.Evaluate(t.Root().Derivative())
+	if err != nil {
+		return nil, err
+	}
+	for i := range weights {
+		if bls.EqualZero(&weights[i]) {
+			return nil, fmt.Errorf("%w: point %d occurs more than once", ErrDuplicatePoint, i)
+		}
+	}
+	bls.BatchInvModFr(weights)
+
+	// the leaves are summed directly
+	level := make([]Poly, len(t.leafPoints))
+	offset := 0
+	for i, points := range t.leafPoints {
+		sum := make(Poly, len(points), len(points))
+		for j := range sum {
+			bls.CopyFr(&sum[j], &bls.ZERO)
+		}
+		var c bls.Fr
+		for j := range points {
+			bls.MulModFr(&c, &ys[offset+j], &weights[offset+j])
+			sum = sum.Add(t.levels[0][i].DivLinear(&points[j]).Scale(&c))
+		}
+		level[i] = sum
+		offset += len(points)
+	}
+	// then combined up the tree: N = N_left * M_right + N_right * M_left
+	for l := 0; l+1 < len(t.levels); l++ {
+		polys := t.levels[l]
+		next := make([]Poly, 0, len(t.levels[l+1]))
+		for i := 0; i+1 < len(level); i += 2 {
+			a, err := t.fs.MulPolys(level[i], polys[i+1])
+			if err != nil {
+				return nil, err
+			}
+			b, err := t.fs.MulPolys(level[i+1], polys[i])
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, a.Add(b))
+		}
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	out := level[0]
+	if len(out) > count {
+		out = out[:count]
+	}
+	return out, nil
+}
+
+// EvalPolyAtPoints evaluates the polynomial at all the points, with a subproduct tree.
+// For many points this is much faster than evaluating the points one by one.
+func (fs *FFTSettings) EvalPolyAtPoints(p Poly, xs []bls.Fr) ([]bls.Fr, error) {
+	t, err := fs.NewSubproductTree(xs)
+	if err != nil {
+		return nil, err
+	}
+	return t.Evaluate(p)
+}
+
+// InterpolatePolyAtPoints computes the polynomial of degree < len(xs) with p(xs[i]) = ys[i], with a subproduct tree.
+// For many points this is much faster than InterpolatePoly.
+func (fs *FFTSettings) InterpolatePolyAtPoints(xs []bls.Fr, ys []bls.Fr) (Poly, error) {
+	if len(xs) != len(ys) {
+		return nil, fmt.Errorf("%w: got %d points and %d values", ErrLengthMismatch, len(xs), len(ys))
+	}
+	t, err := fs.NewSubproductTree(xs)
+	if err != nil {
+		return nil, err
+	}
+	return t.Interpolate(ys)
+}
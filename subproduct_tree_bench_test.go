@@ -0,0 +1,53 @@
+package kzg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func BenchmarkEvalPolyAtPoints(b *testing.B) {
+	fs := NewFFTSettings(13)
+	poly := randomPoly(4096)
+	for _, n := range []int{64, 256, 1024, 4095} {
+		xs := randomPoly(n)
+		b.Run(fmt.Sprintf("tree_points_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := fs.EvalPolyAtPoints(poly, xs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("naive_points_%d", n), func(b *testing.B) {
+			out := make([]bls.Fr, n)
+			for i := 0; i < b.N; i++ {
+				for j := range xs {
+					bls.EvalPolyAt(&out[j], poly, &xs[j])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkInterpolatePolyAtPoints(b *testing.B) {
+	fs := NewFFTSettings(13)
+	for _, n := range []int{64, 256, 1024} {
+		xs := randomPoly(n)
+		ys := randomPoly(n)
+		b.Run(fmt.Sprintf("tree_points_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := fs.InterpolatePolyAtPoints(xs, ys); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("lagrange_points_%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := InterpolatePoly(xs, ys); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/protolambda/go-kzg/bls"
+)
+
+func TestSubproductTree(t *testing.T) {
+	fs := NewFFTSettings(10)
+	for _, n := range []int{1, 5, 16, 17, 50, 100, 256, 300} {
+		t.Run(fmt.Sprintf("points_%d", n), func(t *testing.T) {
+			xs := randomPoly(n)
+			poly := randomPoly(2*n + 3)
+			got, err := fs.EvalPolyAtPoints(poly, xs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := make([]bls.Fr, n)
+			for i := range xs {
+				bls.EvalPolyAt(&expected[i], poly, &xs[i])
+			}
+			checkPolyEqual(t, got, expected)
+
+			interpolated, err := fs.InterpolatePolyAtPoints(xs, expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(interpolated) != n {
+				t.Fatalf("expected interpolated poly of length %d, got %d", n, len(interpolated))
+			}
+			for i := range xs {
+				if y := interpolated.Eval(&xs[i]); !bls.EqualFr(y, &expected[i]) {
+					t.Fatalf("interpolated poly mismatch at point %d", i)
+				}
+			}
+			// a polynomial of low enough degree is recovered exactly
+			small := randomPoly(n)
+			ys, err := fs.EvalPolyAtPoints(small, xs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := fs.InterpolatePolyAtPoints(xs, ys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPolyEqual(t, back, small)
+		})
+	}
+	xs := randomPoly(40)
+	bls.CopyFr(&xs[30], &xs[3])
+	if _, err := fs.InterpolatePolyAtPoints(xs, make([]bls.Fr, 40)); !errors.Is(err, ErrDuplicatePoint) {
+		t.Fatalf("expected duplicate point error, got: %v", err)
+	}
+	if _, err := fs.NewSubproductTree(randomPoly(1024)); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+}
@@ -38,16 +38,6 @@ func (fs *FFTSettings) makeZeroPolyMulLeaf(dst []bls.Fr, indices []uint64, domai
 	}
 }
 
-// Copy all of the values of poly into out, and fill the remainder of out with zeroes.
-func padPoly(out []bls.Fr, poly []bls.Fr) {
-	for i := 0; i < len(poly); i++ {
-		bls.CopyFr(&out[i], &poly[i])
-	}
-	for i := len(poly); i < len(out); i++ {
-		bls.CopyFr(&out[i], &bls.ZERO)
-	}
-}
-
 // Calculate the product of the input polynomials via convolution.
 // Pad the polynomials in ps, perform FFTs, point-wise multiply the results together,
 // and apply an inverse FFT to the result.
@@ -84,7 +74,7 @@ func (fs *FFTSettings) reduceLeaves(scratch []bls.Fr, dst []bls.Fr, ps [][]bls.F
 
 	// Do the last partial first: it is no longer than the others and the padding can remain in place for the rest.
 	last := uint64(len(ps) - 1)
-	padPoly(pPadded, ps[last])
+	Poly(ps[last]).padInto(pPadded)
 	if err := fs.InplaceFFT(pPadded, mulEvalPs, false); err != nil {
 		panic(err)
 	}
@@ -114,14 +104,33 @@ func (fs *FFTSettings) reduceLeaves(scratch []bls.Fr, dst []bls.Fr, ps [][]bls.F
 //
 // Also calculates the FFT (the "evaluation polynomial").
 func (fs *FFTSettings) ZeroPolyViaMultiplication(missingIndices []uint64, length uint64) ([]bls.Fr, []bls.Fr) {
+	zeroEval, zeroPoly, err := fs.TryZeroPolyViaMultiplication(missingIndices, length)
+	if err != nil {
+		panic(err)
+	}
+	return zeroEval, zeroPoly
+}
+
+// TryZeroPolyViaMultiplication is the error-returning variant of ZeroPolyViaMultiplication.
+// Missing indices must be within the length, and there must be fewer missing indices than the length itself.
+func (fs *FFTSettings) TryZeroPolyViaMultiplication(missingIndices []uint64, length uint64) ([]bls.Fr, []bls.Fr, error) {
 	if len(missingIndices) == 0 {
-		return make([]bls.Fr, length, length), make([]bls.Fr, length, length)
+		return make([]bls.Fr, length, length), make([]bls.Fr, length, length), nil
 	}
 	if length > fs.MaxWidth {
-		panic("domain too small for requested length")
+		return nil, nil, fmt.Errorf("%w: requested length %d, max width %d", ErrDomainTooSmall, length, fs.MaxWidth)
 	}
 	if !bls.IsPowerOfTwo(length) {
-		panic("length not a power of two")
+		return nil, nil, fmt.Errorf("%w: length %d", ErrNotPowerOfTwo, length)
+	}
+	if uint64(len(missingIndices)) >= length {
+		return nil, nil, fmt.Errorf("%w: expected less than %d missing indices, got %d",
+			ErrSizeTooLarge, length, len(missingIndices))
+	}
+	for _, v := range missingIndices {
+		if v >= length {
+			return nil, nil, fmt.Errorf("%w: missing index %d is out of range, length is %d", ErrInvalidIndex, v, length)
+		}
 	}
 	domainStride := fs.MaxWidth / length
 	perLeafPoly := uint64(64)
@@ -136,9 +145,9 @@ func (fs *FFTSettings) ZeroPolyViaMultiplication(missingIndices []uint64, length
 		zeroPoly = zeroPoly[:length]
 		zeroEval, err := fs.FFT(zeroPoly, false)
 		if err != nil {
-			panic(err)
+			return nil, nil, err
 		}
-		return zeroEval, zeroPoly
+		return zeroEval, zeroPoly, nil
 	}
 
 	leafCount := (uint64(len(missingIndices)) + perLeaf - 1) / perLeaf
@@ -205,13 +214,51 @@ func (fs *FFTSettings) ZeroPolyViaMultiplication(missingIndices []uint64, length
 	if zl := uint64(len(zeroPoly)); zl < length {
 		zeroPoly = append(zeroPoly, make([]bls.Fr, length-zl, length-zl)...)
 	} else if zl > length {
-		panic("expected output smaller or equal to input length")
+		return nil, nil, fmt.Errorf("%w: expected output smaller or equal to input length", ErrSizeTooLarge)
 	}
 
 	zeroEval, err := fs.FFT(zeroPoly, false)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 
-	return zeroEval, zeroPoly
+	return zeroEval, zeroPoly, nil
+}
+
+// ZeroPolyForCosets calculates the minimal polynomial that evaluates to zero for all the points of the missing cosets.
+//
+// The domain of the given length is split into length/cosetLen cosets of the subgroup of cosetLen-th roots of unity,
+// coset j is made of the points r^(j + i*length/cosetLen) for i in [0, cosetLen), with r the length-th root of unity.
+// For all points x of coset j, x^cosetLen is the same value s_j, so the zero polynomial is the product of all
+// (x^cosetLen - s_j), which is computed as the zero polynomial of the s_j over the smaller domain, with spread out coefficients.
+//
+// Also calculates the FFT (the "evaluation polynomial"). This matches the ZeroPolyFn signature when wrapped,
+// to recover data of which whole cosets are missing.
+func (fs *FFTSettings) ZeroPolyForCosets(missingCosets []uint64, cosetLen uint64, length uint64) ([]bls.Fr, []bls.Fr, error) {
+	if !bls.IsPowerOfTwo(cosetLen) {
+		return nil, nil, fmt.Errorf("%w: coset length %d", ErrNotPowerOfTwo, cosetLen)
+	}
+	if cosetLen > length {
+		return nil, nil, fmt.Errorf("%w: coset length %d is larger than length %d", ErrSizeTooLarge, cosetLen, length)
+	}
+	if !bls.IsPowerOfTwo(length) {
+		return nil, nil, fmt.Errorf("%w: length %d", ErrNotPowerOfTwo, length)
+	}
+	cosetCount := length / cosetLen
+	_, shortZeroPoly, err := fs.TryZeroPolyViaMultiplication(missingCosets, cosetCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	zeroPoly := make([]bls.Fr, length, length)
+	if len(missingCosets) == 0 {
+		return zeroPoly, make([]bls.Fr, length, length), nil
+	}
+	for i := uint64(0); i < cosetCount; i++ {
+		bls.CopyFr(&zeroPoly[i*cosetLen], &shortZeroPoly[i])
+	}
+	zeroEval, err := fs.FFT(zeroPoly, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return zeroEval, zeroPoly, nil
 }
@@ -1,6 +1,7 @@
 package kzg
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -259,3 +260,64 @@ func TestFFTSettings_ZeroPolyViaMultiplication_Parametrized(t *testing.T) {
 		})
 	}
 }
+
+func TestFFTSettings_TryZeroPolyViaMultiplication(t *testing.T) {
+	fs := NewFFTSettings(4)
+	if _, _, err := fs.TryZeroPolyViaMultiplication([]uint64{1, 2}, 32); !errors.Is(err, ErrDomainTooSmall) {
+		t.Fatalf("expected domain too small error, got: %v", err)
+	}
+	if _, _, err := fs.TryZeroPolyViaMultiplication([]uint64{1, 2}, 12); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, _, err := fs.TryZeroPolyViaMultiplication([]uint64{1, 8}, 8); !errors.Is(err, ErrInvalidIndex) {
+		t.Fatalf("expected invalid index error, got: %v", err)
+	}
+	if _, _, err := fs.TryZeroPolyViaMultiplication([]uint64{0, 1, 2, 3}, 4); !errors.Is(err, ErrSizeTooLarge) {
+		t.Fatalf("expected size too large error, got: %v", err)
+	}
+	zeroEval, zeroPoly, err := fs.TryZeroPolyViaMultiplication([]uint64{1, 2}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zeroEval) != 8 || len(zeroPoly) != 8 {
+		t.Fatalf("unexpected output lengths: %d, %d", len(zeroEval), len(zeroPoly))
+	}
+}
+
+func TestFFTSettings_ZeroPolyForCosets(t *testing.T) {
+	fs := NewFFTSettings(6)
+	length := uint64(32)
+	cosetLen := uint64(4)
+	cosetCount := length / cosetLen
+	missingCosets := []uint64{1, 2, 6}
+	zeroEval, zeroPoly, err := fs.ZeroPolyForCosets(missingCosets, cosetLen, length)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(zeroEval)) != length || uint64(len(zeroPoly)) != length {
+		t.Fatalf("unexpected output lengths: %d, %d", len(zeroEval), len(zeroPoly))
+	}
+	isMissing := make(map[uint64]bool)
+	for _, j := range missingCosets {
+		isMissing[j] = true
+	}
+	stride := fs.MaxWidth / length
+	for i := uint64(0); i < length; i++ {
+		var x, y bls.Fr
+		bls.CopyFr(&x, &fs.ExpandedRootsOfUnity[i*stride])
+		bls.EvalPolyAt(&y, zeroPoly, &x)
+		if !bls.EqualFr(&y, &zeroEval[i]) {
+			t.Fatalf("zero eval %d does not match zero poly", i)
+		}
+		if isMissing[i%cosetCount] != bls.EqualZero(&y) {
+			t.Fatalf("zero poly at index %d (coset %d): expected zero: %v, got %s",
+				i, i%cosetCount, isMissing[i%cosetCount], bls.FrStr(&y))
+		}
+	}
+	if _, _, err := fs.ZeroPolyForCosets(missingCosets, 3, length); !errors.Is(err, ErrNotPowerOfTwo) {
+		t.Fatalf("expected not power of two error, got: %v", err)
+	}
+	if _, _, err := fs.ZeroPolyForCosets([]uint64{8}, cosetLen, length); !errors.Is(err, ErrInvalidIndex) {
+		t.Fatalf("expected invalid index error, got: %v", err)
+	}
+}